@@ -0,0 +1,46 @@
+package shutdown
+
+// Appender is the narrowed view of a Closure that can only register closers.
+type Appender interface {
+	Append(closer Closer) // Appends a new closer
+}
+
+// readOnly hides everything but Append of the wrapped appender.
+type readOnly struct {
+	appender Appender // The wrapped appender
+}
+
+// ReadOnly returns a view of the closure that can be passed to third-party libraries,
+// letting them register cleanups without the ability to trigger Close.
+// The returned Appender cannot be type-asserted back to the Closure.
+func ReadOnly(a Appender) Appender {
+	return readOnly{appender: a}
+}
+
+// Append appends the closer to the wrapped appender.
+func (r readOnly) Append(closer Closer) {
+	r.appender.Append(closer)
+}
+
+// pkgAppender is the Appender view of the global closure.
+type pkgAppender struct{}
+
+// Append appends the closer to the global closure.
+func (pkgAppender) Append(closer Closer) {
+	checkStrict(packageEntry(closer, callerPackage(1)))
+	appendPackage(closer)
+}
+
+// PackageAppender returns the Appender view of the global closure,
+// for components that should only register closers.
+// It follows the global closure replaced with SetPackageClosure.
+func PackageAppender() Appender {
+	return pkgAppender{}
+}
+
+// AppendAll appends all the given closers to the appender, in order.
+func AppendAll(a Appender, closers ...Closer) {
+	for _, closer := range closers {
+		a.Append(closer)
+	}
+}
@@ -0,0 +1,26 @@
+package shutdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	lifo := &Lifo{}
+	appender := ReadOnly(lifo)
+
+	appender.Append(&mockCloser{})
+	assert.Equal(t, 1, lifo.Pending())
+
+	_, isCloser := appender.(Closer)
+	assert.False(t, isCloser, "Expected the read-only view not to expose Close")
+}
+
+func TestPackageAppender(t *testing.T) {
+	lifo := &Lifo{}
+	resetPackage(lifo)
+
+	AppendAll(PackageAppender(), &mockCloser{}, &mockCloser{})
+	assert.Equal(t, 2, lifo.Pending())
+}
@@ -0,0 +1,61 @@
+package shutdown
+
+import (
+	"runtime"
+	"strings"
+	"time"
+)
+
+// PackageStats aggregates the results of the closers appended by one Go package.
+type PackageStats struct {
+	Closers  int           // Number of closed closers
+	Failures int           // Number of closers that returned an error
+	Duration time.Duration // Cumulative close duration
+}
+
+// ByPackage aggregates the results by the Go package that appended the closers,
+// helping large codebases find which component slows the shutdown down.
+func (r *Report) ByPackage() map[string]PackageStats {
+	stats := make(map[string]PackageStats)
+
+	for _, res := range r.Results {
+		s := stats[res.Package]
+		s.Closers++
+		s.Duration += res.Duration
+
+		if res.Err != nil {
+			s.Failures++
+		}
+
+		stats[res.Package] = s
+	}
+
+	return stats
+}
+
+// callerPackage returns the import path of the package of the caller, skip frames above.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	return packageOf(fn.Name())
+}
+
+// packageOf extracts the package import path from a fully qualified function name,
+// e.g. "github.com/org/app/db.(*Pool).Close" results in "github.com/org/app/db".
+func packageOf(funcName string) string {
+	lastSlash := strings.LastIndex(funcName, "/")
+
+	if dot := strings.Index(funcName[lastSlash+1:], "."); dot >= 0 {
+		return funcName[:lastSlash+1+dot]
+	}
+
+	return funcName
+}
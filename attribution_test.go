@@ -0,0 +1,37 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageOf(t *testing.T) {
+	assert.Equal(t, "github.com/org/app/db", packageOf("github.com/org/app/db.(*Pool).Close"))
+	assert.Equal(t, "github.com/org/app/db", packageOf("github.com/org/app/db.New.func1"))
+	assert.Equal(t, "main", packageOf("main.main"))
+}
+
+func TestReport_ByPackage(t *testing.T) {
+	closure := Upgrade(&Fifo{})
+	closure.Append(Fn(func() error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}))
+	closure.Append(Fn(func() error { return errors.New("close error") }))
+
+	report, err := closure.CloseContext(context.Background())
+	require.Error(t, err)
+
+	stats := report.ByPackage()
+	if assert.Contains(t, stats, "github.com/partyzanex/shutdown") {
+		s := stats["github.com/partyzanex/shutdown"]
+		assert.Equal(t, 2, s.Closers)
+		assert.Equal(t, 1, s.Failures)
+		assert.GreaterOrEqual(t, s.Duration, time.Millisecond)
+	}
+}
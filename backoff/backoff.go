@@ -0,0 +1,61 @@
+// Package backoff provides the retry delays used by the shutdown retry helpers.
+package backoff
+
+import "time"
+
+// Default values used for the zero fields of Config.
+const (
+	DefaultAttempts   = 3
+	DefaultInitial    = 100 * time.Millisecond
+	DefaultMax        = 5 * time.Second
+	DefaultMultiplier = 2
+)
+
+// Config configures an exponential backoff.
+type Config struct {
+	Attempts   int           // Maximum number of attempts, including the first one
+	Initial    time.Duration // Delay before the second attempt
+	Max        time.Duration // Upper bound of the delay
+	Multiplier float64       // Factor applied to the delay after each attempt
+}
+
+// WithDefaults returns a copy of the config with the zero fields set to the defaults.
+func (c Config) WithDefaults() Config {
+	if c.Attempts <= 0 {
+		c.Attempts = DefaultAttempts
+	}
+
+	if c.Initial <= 0 {
+		c.Initial = DefaultInitial
+	}
+
+	if c.Max <= 0 {
+		c.Max = DefaultMax
+	}
+
+	if c.Multiplier < 1 {
+		c.Multiplier = DefaultMultiplier
+	}
+
+	return c
+}
+
+// Delay returns the delay before the given attempt, counting from zero.
+// The first attempt has no delay.
+func (c Config) Delay(attempt int) time.Duration {
+	if attempt <= 0 {
+		return 0
+	}
+
+	delay := float64(c.Initial)
+
+	for i := 1; i < attempt && delay < float64(c.Max); i++ {
+		delay *= c.Multiplier
+	}
+
+	if delay > float64(c.Max) {
+		return c.Max
+	}
+
+	return time.Duration(delay)
+}
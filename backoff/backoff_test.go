@@ -0,0 +1,30 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Delay(t *testing.T) {
+	cfg := Config{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 3}
+
+	assert.Equal(t, time.Duration(0), cfg.Delay(0))
+	assert.Equal(t, 100*time.Millisecond, cfg.Delay(1))
+	assert.Equal(t, 300*time.Millisecond, cfg.Delay(2))
+	assert.Equal(t, 900*time.Millisecond, cfg.Delay(3))
+	assert.Equal(t, time.Second, cfg.Delay(4))
+}
+
+func TestConfig_WithDefaults(t *testing.T) {
+	assert.Equal(t, Config{
+		Attempts:   DefaultAttempts,
+		Initial:    DefaultInitial,
+		Max:        DefaultMax,
+		Multiplier: DefaultMultiplier,
+	}, Config{}.WithDefaults())
+
+	cfg := Config{Attempts: 5, Initial: time.Second, Max: time.Minute, Multiplier: 1.5}
+	assert.Equal(t, cfg, cfg.WithDefaults())
+}
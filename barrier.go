@@ -0,0 +1,100 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBarrierTimeout is returned (wrapped) when a barrier is not passed within its timeout.
+var ErrBarrierTimeout = errors.New("shutdown: barrier timed out")
+
+// Barrier is a closer blocking the progression to the next closers until an external condition
+// signals completion, e.g. the load balancer confirms the deregistration or a message queue
+// reports zero lag. The condition is either signalled with Release or polled, see PollBarrier.
+type Barrier struct {
+	name     string                                  // The name of the barrier, used in errors and plans
+	timeout  time.Duration                           // The maximum wait, zero means no own timeout
+	interval time.Duration                           // The polling interval of cond
+	cond     func(ctx context.Context) (bool, error) // The polled condition, may be nil
+	released chan struct{}                           // Closed by Release
+	once     sync.Once                               // Ensures that released is closed once
+}
+
+// NewBarrier returns a barrier passed once Release is called, or failing after the timeout.
+func NewBarrier(name string, timeout time.Duration) *Barrier {
+	return &Barrier{name: name, timeout: timeout, released: make(chan struct{})}
+}
+
+// PollBarrier returns a barrier passed once cond reports true, polled every interval,
+// or failing after the timeout. An error of cond fails the barrier right away.
+func PollBarrier(name string, timeout, interval time.Duration, cond func(ctx context.Context) (bool, error)) *Barrier {
+	b := NewBarrier(name, timeout)
+	b.interval, b.cond = interval, cond
+
+	return b
+}
+
+// Release signals that the condition of the barrier is met. It is safe to call it several times.
+func (b *Barrier) Release() {
+	b.once.Do(func() { close(b.released) })
+}
+
+// Name returns the name of the barrier.
+func (b *Barrier) Name() string {
+	return b.name
+}
+
+// CloseContext blocks until the barrier is passed, its timeout elapses or the context is done.
+func (b *Barrier) CloseContext(ctx context.Context) error {
+	start := time.Now()
+
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+
+	var tick <-chan time.Time
+
+	if b.cond != nil {
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		if b.cond != nil {
+			ok, err := b.cond(ctx)
+			if err != nil {
+				return fmt.Errorf("shutdown: barrier %s: %w", b.name, err)
+			}
+
+			if ok {
+				b.Release()
+			}
+		}
+
+		select {
+		case <-b.released:
+			return nil
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("shutdown: barrier %s not passed after %s: %w: %w",
+					b.name, time.Since(start).Round(time.Millisecond), ErrBarrierTimeout, ctx.Err())
+			}
+
+			return fmt.Errorf("shutdown: barrier %s: %w", b.name, ctx.Err())
+		case <-tick:
+		}
+	}
+}
+
+// Close blocks until the barrier is passed or its timeout elapses.
+func (b *Barrier) Close() error {
+	return b.CloseContext(context.Background())
+}
@@ -0,0 +1,56 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarrier(t *testing.T) {
+	b := NewBarrier("lb-deregistration", time.Second)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		b.Release()
+		b.Release()
+	}()
+
+	assert.NoError(t, b.Close())
+	assert.Equal(t, "lb-deregistration", b.Name())
+}
+
+func TestBarrier_Timeout(t *testing.T) {
+	err := NewBarrier("lb-deregistration", 20*time.Millisecond).Close()
+	assert.ErrorIs(t, err, ErrBarrierTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "barrier lb-deregistration not passed after")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = NewBarrier("cancelled", 0).CloseContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrBarrierTimeout)
+}
+
+func TestPollBarrier(t *testing.T) {
+	lag := 3
+
+	b := PollBarrier("queue-lag", time.Second, time.Millisecond, func(context.Context) (bool, error) {
+		lag--
+		return lag == 0, nil
+	})
+
+	assert.NoError(t, b.Close())
+	assert.Zero(t, lag)
+
+	errLag := errors.New("lag unavailable")
+	b = PollBarrier("queue-lag", time.Second, time.Millisecond, func(context.Context) (bool, error) {
+		return false, errLag
+	})
+
+	assert.ErrorIs(t, b.Close(), errLag)
+}
@@ -0,0 +1,84 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+)
+
+// Broadcast fans a trigger out to any number of waiters: each waiter gets its own
+// channel or context, instead of racing the others over a single signal channel.
+type Broadcast struct {
+	done   chan struct{}      // Closed when the trigger fires or the broadcast is closed
+	cancel context.CancelFunc // Stops waiting for the trigger
+	once   sync.Once          // Ensures that done is closed once
+	cause  string             // The cause returned by the trigger
+	err    error              // The error returned by the trigger
+}
+
+// NotifyAll starts waiting for the trigger and returns the broadcast notifying all its waiters
+// once the trigger fires:
+//
+//	b := shutdown.NotifyAll(shutdown.SignalTrigger(os.Interrupt, syscall.SIGTERM))
+//	go worker(b.Context(ctx))
+//	go consumer(b.Done())
+func NotifyAll(trigger Trigger) *Broadcast {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b := &Broadcast{done: make(chan struct{}), cancel: cancel}
+
+	go func() {
+		cause, err := trigger.Wait(ctx)
+		b.fire(cause, err)
+	}()
+
+	return b
+}
+
+// fire records the outcome of the trigger and notifies the waiters.
+func (b *Broadcast) fire(cause string, err error) {
+	b.once.Do(func() {
+		b.cause, b.err = cause, err
+		close(b.done)
+	})
+}
+
+// Done returns a channel closed once the trigger fires or the broadcast is closed.
+func (b *Broadcast) Done() <-chan struct{} {
+	return b.done
+}
+
+// Context returns a context derived from the parent, cancelled once the trigger fires.
+// The returned cancel function releases the resources of the context.
+func (b *Broadcast) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-b.done:
+			cancel()
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// Wait blocks until the trigger fires or the context is done, so the broadcast is a Trigger itself.
+// It returns the cause and the error of the trigger, or the context error.
+func (b *Broadcast) Wait(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-b.done:
+		return b.cause, b.err
+	}
+}
+
+// Close stops waiting for the trigger and notifies the waiters with context.Canceled.
+// It always returns nil.
+func (b *Broadcast) Close() error {
+	b.cancel()
+	b.fire("", context.Canceled)
+
+	return nil
+}
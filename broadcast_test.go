@@ -0,0 +1,68 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyAll(t *testing.T) {
+	fire := make(chan struct{})
+	b := NotifyAll(TriggerFunc(func(ctx context.Context) (string, error) {
+		<-fire
+		return "test", nil
+	}))
+	defer b.Close()
+
+	var wg sync.WaitGroup
+
+	causes := make(chan string, 3)
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			cause, err := b.Wait(context.Background())
+			assert.NoError(t, err)
+			causes <- cause
+		}()
+	}
+
+	ctx, cancel := b.Context(context.Background())
+	defer cancel()
+
+	close(fire)
+	wg.Wait()
+	close(causes)
+
+	for cause := range causes {
+		assert.Equal(t, "test", cause)
+	}
+
+	<-b.Done()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestNotifyAll_Close(t *testing.T) {
+	b := NotifyAll(TriggerFunc(func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := b.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.NoError(t, b.Close())
+
+	_, err = b.Wait(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,85 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// IsCancellation reports whether the error is caused by the shutdown context being cancelled
+// or timed out, which is expected once the deadline passes, rather than by a genuine closer failure.
+// The error is a cancellation only if the context is done and the error wraps the context error:
+// a context.DeadlineExceeded of a closer's own I/O timeout, while the context is live, is a failure.
+func IsCancellation(ctx context.Context, err error) bool {
+	return isCancellation(ctx.Err(), err)
+}
+
+// isCancellation reports whether the error wraps the error of the done shutdown context, if not nil.
+func isCancellation(ctxErr, err error) bool {
+	return ctxErr != nil && errors.Is(err, ctxErr)
+}
+
+// SplitCancellation splits the aggregated error into genuine closer failures
+// and errors caused by the cancellation of the shutdown context, see IsCancellation.
+func SplitCancellation(ctx context.Context, err error) (failures, cancellations error) {
+	for _, e := range multierr.Errors(err) {
+		if IsCancellation(ctx, e) {
+			cancellations = multierr.Append(cancellations, e)
+		} else {
+			failures = multierr.Append(failures, e)
+		}
+	}
+
+	return failures, cancellations
+}
+
+// Failures returns the results of the closers that genuinely failed.
+func (r *Report) Failures() []Result {
+	return r.filter(func(res Result) bool { return res.Err != nil && !isCancellation(r.Cancelled, res.Err) })
+}
+
+// Cancellations returns the results of the closers interrupted by the cancellation of the shutdown context,
+// see Report.Cancelled.
+func (r *Report) Cancellations() []Result {
+	return r.filter(func(res Result) bool { return isCancellation(r.Cancelled, res.Err) })
+}
+
+func (r *Report) filter(keep func(Result) bool) []Result {
+	var results []Result
+
+	for _, res := range r.Results {
+		if keep(res) {
+			results = append(results, res)
+		}
+	}
+
+	return results
+}
+
+// ignoreCancellation drops the cancellation errors returned by the wrapped Closure.
+type ignoreCancellation struct {
+	decorator // The wrapped closure
+}
+
+// IgnoreCancellation wraps the closure so that only genuine closer failures are returned,
+// excluding the errors caused by the cancellation of the shutdown context.
+func IgnoreCancellation(c Closure) Closure {
+	return &ignoreCancellation{decorator: decorator{c}}
+}
+
+// CloseContext closes the wrapped closure and returns the genuine failures only.
+func (i *ignoreCancellation) CloseContext(ctx context.Context) error {
+	failures, _ := SplitCancellation(ctx, i.Closure.CloseContext(ctx))
+	return failures
+}
+
+// Close closes the wrapped closure without context support and returns the genuine failures only.
+func (i *ignoreCancellation) Close() error {
+	return i.CloseContext(context.Background())
+}
+
+// WithContext associates the closure with the given context.
+func (i *ignoreCancellation) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, i)
+}
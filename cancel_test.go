@@ -0,0 +1,78 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
+)
+
+func TestSplitCancellation(t *testing.T) {
+	failure := errors.New("close error")
+	err := multierr.Combine(failure, context.DeadlineExceeded, fmt.Errorf("flush: %w", context.DeadlineExceeded))
+
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second) // Already timed out
+	defer cancel()
+
+	failures, cancellations := SplitCancellation(ctx, err)
+	assert.Equal(t, []error{failure}, multierr.Errors(failures))
+	assert.Len(t, multierr.Errors(cancellations), 2)
+
+	// While the context is live, a deadline error is the closer's own timeout, a genuine failure.
+	failures, cancellations = SplitCancellation(context.Background(), err)
+	assert.Len(t, multierr.Errors(failures), 3)
+	assert.NoError(t, cancellations)
+
+	failures, cancellations = SplitCancellation(ctx, nil)
+	assert.NoError(t, failures)
+	assert.NoError(t, cancellations)
+}
+
+func TestIgnoreCancellation(t *testing.T) {
+	closure := IgnoreCancellation(&Fifo{})
+	closure.Append(Fn(func() error { return errors.New("close error") }))
+	closure.Append(Fn(func() error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := closure.CloseContext(ctx)
+	assert.EqualError(t, err, "close error")
+}
+
+func TestIgnoreCancellation_OwnTimeout(t *testing.T) {
+	closure := IgnoreCancellation(&Fifo{})
+	closure.Append(Fn(func() error {
+		return fmt.Errorf("dial: %w", context.DeadlineExceeded) // The closer's own I/O timeout
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	assert.ErrorIs(t, closure.CloseContext(ctx), context.DeadlineExceeded)
+}
+
+func TestReport_Cancellations(t *testing.T) {
+	report := &Report{Cancelled: context.Canceled, Results: []Result{
+		{Entry: Entry{Name: "ok"}},
+		{Entry: Entry{Name: "failed"}, Err: errors.New("close error")},
+		{Entry: Entry{Name: "timed out"}, Err: context.DeadlineExceeded}, // Its own timeout
+		{Entry: Entry{Name: "cancelled"}, Err: context.Canceled},
+	}}
+
+	if failures := report.Failures(); assert.Len(t, failures, 2) {
+		assert.Equal(t, "failed", failures[0].Name)
+		assert.Equal(t, "timed out", failures[1].Name)
+	}
+
+	if cancellations := report.Cancellations(); assert.Len(t, cancellations, 1) {
+		assert.Equal(t, "cancelled", cancellations[0].Name)
+	}
+}
@@ -0,0 +1,37 @@
+package shutdown
+
+import "context"
+
+// RequestCloser closes a goroutine-owned resource via the request/acknowledge pattern.
+type RequestCloser struct {
+	request chan<- struct{} // Channel to request the close
+	done    <-chan error    // Channel acknowledging the close with its result
+}
+
+// ChannelCloser returns a closer for actor-style goroutines that own a resource:
+// it sends a close request on the request channel and waits for the acknowledgement
+// (the close result) on the done channel.
+func ChannelCloser(request chan<- struct{}, done <-chan error) *RequestCloser {
+	return &RequestCloser{request: request, done: done}
+}
+
+// CloseContext requests the close and waits for the acknowledgement until the context is done.
+func (c *RequestCloser) CloseContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out before the request is accepted.
+		return ctx.Err()
+	case c.request <- struct{}{}:
+	}
+
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out before the acknowledgement.
+		return ctx.Err()
+	case err := <-c.done:
+		return err
+	}
+}
+
+// Close requests the close and waits for the acknowledgement without a deadline.
+func (c *RequestCloser) Close() error {
+	return c.CloseContext(context.Background())
+}
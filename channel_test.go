@@ -0,0 +1,34 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelCloser(t *testing.T) {
+	request := make(chan struct{})
+	done := make(chan error, 1)
+	expectedErr := errors.New("actor error")
+
+	go func() {
+		<-request
+		done <- expectedErr
+	}()
+
+	assert.ErrorIs(t, ChannelCloser(request, done).Close(), expectedErr)
+}
+
+func TestChannelCloser_Deadline(t *testing.T) {
+	request := make(chan struct{}, 1)
+	done := make(chan error)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// The request is accepted, but never acknowledged.
+	assert.ErrorIs(t, ChannelCloser(request, done).CloseContext(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,226 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AnomalyKind is the kind of a clock anomaly observed during a close sequence.
+type AnomalyKind string
+
+const (
+	// AnomalyClockJump is a jump of the wall clock, e.g. an NTP step or a host suspend.
+	// The deadlines use the monotonic clock and are not affected, but the timestamps of the logs are.
+	AnomalyClockJump AnomalyKind = "clock jump"
+	// AnomalyPause is a pause of the whole process, e.g. a frozen cgroup, a paused container or a stopped VM.
+	// The monotonic clock keeps running meanwhile, so the timeouts of the closers running
+	// during a pause are extended by its duration, not to misattribute the timeout to the closer.
+	AnomalyPause AnomalyKind = "pause"
+)
+
+// Anomaly is a clock anomaly observed during a close sequence.
+type Anomaly struct {
+	Kind     AnomalyKind   // The kind of the anomaly
+	At       time.Time     // When the anomaly was observed
+	Duration time.Duration // The size of the pause, or of the wall clock jump, negative if backwards
+}
+
+// String returns the description of the anomaly, e.g. "pause of 12s".
+func (a Anomaly) String() string {
+	return fmt.Sprintf("%s of %s", a.Kind, a.Duration.Round(time.Millisecond))
+}
+
+const (
+	clockWatchInterval    = 100 * time.Millisecond // The interval of the clock checks during a close sequence
+	clockAnomalyThreshold = time.Second            // The smallest pause or wall clock jump reported
+)
+
+// clockWatcherKey is the context key of the clock watcher of the current close sequence.
+type clockWatcherKey struct{}
+
+// clockWatcher watches the clocks during a close sequence, collecting the anomalies.
+// The timeouts of the closers started by the sequence are extended by the pauses, see withBudget.
+type clockWatcher struct {
+	mx        sync.Mutex    // Mutex for thread safety of the fields below
+	last      time.Time     // The time of the last check
+	paused    time.Duration // The total duration of the observed pauses
+	anomalies []Anomaly     // The observed anomalies
+	interval  time.Duration // The interval of the checks
+	threshold time.Duration // The smallest anomaly reported
+	done      chan struct{} // Closed to stop the watcher
+	stopped   chan struct{} // Closed once the watcher is stopped
+}
+
+// watchClock starts watching the clocks, checking them every interval.
+func watchClock(interval, threshold time.Duration) *clockWatcher {
+	w := &clockWatcher{
+		last:      time.Now(),
+		interval:  interval,
+		threshold: threshold,
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(w.stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				w.check()
+			}
+		}
+	}()
+
+	return w
+}
+
+// check checks the clocks, recording the anomalies since the previous check,
+// and returns the total duration of the pauses observed so far.
+func (w *clockWatcher) check() time.Duration {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	now := time.Now()
+	anomalies := detectAnomalies(now, now.Sub(w.last), now.Round(0).Sub(w.last.Round(0)), w.interval, w.threshold)
+	w.last = now
+
+	w.record(anomalies)
+
+	return w.paused
+}
+
+// record records the anomalies; the caller must hold the mutex.
+func (w *clockWatcher) record(anomalies []Anomaly) {
+	for _, a := range anomalies {
+		if a.Kind == AnomalyPause {
+			w.paused += a.Duration
+		}
+	}
+
+	w.anomalies = append(w.anomalies, anomalies...)
+}
+
+// stop stops the watcher and returns the observed anomalies.
+func (w *clockWatcher) stop() []Anomaly {
+	close(w.done)
+	<-w.stopped
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	return w.anomalies
+}
+
+// budgetCtx is a context done once its timeout expires, extended by the pauses
+// observed by the clock watcher in the meantime.
+type budgetCtx struct {
+	context.Context               // The cancellable parent context
+	mx              sync.Mutex    // Mutex for thread safety of the deadline
+	deadline        time.Time     // The current deadline, extended by the pauses
+	expired         atomic.Bool   // Whether the budget has expired
+	timer           *time.Timer   // The timer of the deadline
+	watcher         *clockWatcher // The watcher observing the pauses
+	paused          time.Duration // The total duration of the pauses when the budget started
+}
+
+// withBudget returns a context done once the timeout expires. If the context carries the clock
+// watcher of a close sequence, the timeout is extended by the pauses of the process observed
+// meanwhile, so that a frozen container does not make the closer time out. The parent deadline,
+// set by the caller of the sequence, is not extended.
+func withBudget(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	w, _ := ctx.Value(clockWatcherKey{}).(*clockWatcher)
+	if w == nil {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	w.mx.Lock()
+	paused := w.paused
+	w.mx.Unlock()
+
+	inner, cancel := context.WithCancel(ctx)
+	b := &budgetCtx{Context: inner, deadline: time.Now().Add(timeout), watcher: w, paused: paused}
+
+	b.mx.Lock()
+	b.timer = time.AfterFunc(timeout, func() { b.expire(cancel) })
+	b.mx.Unlock()
+
+	return b, func() {
+		b.mx.Lock()
+		b.timer.Stop()
+		b.mx.Unlock()
+		cancel()
+	}
+}
+
+// expire cancels the context, unless pauses were observed since the start of the budget:
+// then the deadline is extended by them.
+func (b *budgetCtx) expire(cancel context.CancelFunc) {
+	extension := b.watcher.check() - b.paused // Do not wait for the next tick to observe a pause
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if b.Context.Err() != nil {
+		return // Released or cancelled meanwhile
+	}
+
+	if extension > 0 {
+		b.paused += extension
+		b.deadline = b.deadline.Add(extension)
+		b.timer.Reset(time.Until(b.deadline))
+
+		return
+	}
+
+	b.expired.Store(true)
+	cancel()
+}
+
+// Deadline returns the current deadline, or the parent one if earlier.
+func (b *budgetCtx) Deadline() (time.Time, bool) {
+	b.mx.Lock()
+	deadline := b.deadline
+	b.mx.Unlock()
+
+	if parent, ok := b.Context.Deadline(); ok && parent.Before(deadline) {
+		return parent, true
+	}
+
+	return deadline, true
+}
+
+// Err returns context.DeadlineExceeded once the budget has expired, or the error of the parent context.
+func (b *budgetCtx) Err() error {
+	err := b.Context.Err()
+	if err != nil && b.expired.Load() {
+		return context.DeadlineExceeded
+	}
+
+	return err
+}
+
+// detectAnomalies returns the anomalies observed by a clock check at the given time: the monotonic
+// and the wall clock elapsed times since the previous check, expected to be an interval, differ
+// on a wall clock jump, and both exceed the interval on a pause.
+func detectAnomalies(at time.Time, monotonic, wall, interval, threshold time.Duration) []Anomaly {
+	var anomalies []Anomaly
+
+	if jump := wall - monotonic; jump >= threshold || jump <= -threshold {
+		anomalies = append(anomalies, Anomaly{Kind: AnomalyClockJump, At: at, Duration: jump})
+	}
+
+	if pause := monotonic - interval; pause >= threshold {
+		anomalies = append(anomalies, Anomaly{Kind: AnomalyPause, At: at, Duration: pause})
+	}
+
+	return anomalies
+}
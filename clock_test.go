@@ -0,0 +1,80 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	at := time.Now()
+	interval := 100 * time.Millisecond
+
+	assert.Empty(t, detectAnomalies(at, interval, interval, interval, time.Second))
+
+	assert.Equal(t, []Anomaly{{Kind: AnomalyClockJump, At: at, Duration: -time.Hour}},
+		detectAnomalies(at, interval, interval-time.Hour, interval, time.Second))
+
+	anomalies := detectAnomalies(at, interval+5*time.Second, interval+5*time.Second, interval, time.Second)
+	assert.Equal(t, []Anomaly{{Kind: AnomalyPause, At: at, Duration: 5 * time.Second}}, anomalies)
+	assert.Equal(t, "pause of 5s", anomalies[0].String())
+}
+
+func TestWatchClock(t *testing.T) {
+	w := watchClock(time.Millisecond, time.Hour)
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, w.stop())
+
+	// A negative threshold makes every check look like an anomaly.
+	w = watchClock(time.Millisecond, -time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	kinds := map[AnomalyKind]bool{}
+	for _, a := range w.stop() {
+		kinds[a.Kind] = true
+	}
+
+	assert.True(t, kinds[AnomalyPause])
+	assert.True(t, kinds[AnomalyClockJump])
+}
+
+func TestWithBudget(t *testing.T) {
+	w := watchClock(time.Hour, time.Hour)
+	defer w.stop()
+
+	ctx, cancel := withBudget(context.WithValue(context.Background(), clockWatcherKey{}, w), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+
+	// A pause observed meanwhile extends the budget by its duration.
+	w.mx.Lock()
+	w.record([]Anomaly{{Kind: AnomalyPause, At: start, Duration: 50 * time.Millisecond}})
+	w.mx.Unlock()
+
+	<-ctx.Done()
+	assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	extended, _ := ctx.Deadline()
+	assert.Equal(t, 50*time.Millisecond, extended.Sub(deadline))
+}
+
+func TestWithBudget_NoWatcher(t *testing.T) {
+	ctx, cancel := withBudget(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	w := watchClock(time.Hour, time.Hour)
+	defer w.stop()
+
+	ctx, cancel = withBudget(context.WithValue(context.Background(), clockWatcherKey{}, w), time.Hour)
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
@@ -2,58 +2,176 @@ package shutdown
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
 )
 
 // Closer is an alias for io.Closer. It represents an interface that requires a Close method.
 type Closer = io.Closer
 
+// ContextCloser is implemented by closers supporting a shutdown context, such as servers and consumers
+// with a graceful stop, or the strategies themselves. Lifo, Fifo, Group and the other strategies call
+// CloseContext with the context of the close sequence instead of Close, so the deadline is not lost.
+type ContextCloser interface {
+	CloseContext(ctx context.Context) error // Closes the resource, giving up once the context is done
+}
+
 // Closure interface defines methods for appending and closing resources.
+// It combines the Appender role, for components that only register closers,
+// and the Closer role, for the owner that triggers the shutdown.
 type Closure interface {
+	Appender
 	Closer
 
-	Append(closer Closer)                            // Appends a new closer
 	CloseContext(ctx context.Context) error          // Closes resources with context support
 	WithContext(ctx context.Context) context.Context // Sets the context for the closure
 }
 
+// ErrReentrantClose is returned (wrapped, with the name of the closer) when a closer of the global closure
+// calls back into the package-level Close or CloseContext while the global closure is being closed.
+var ErrReentrantClose = errors.New("shutdown: reentrant close")
+
 var (
-	pkgClosure Closure    = &Lifo{} // Default implementation of Closure using Lifo (Last In First Out) strategy
-	mu         sync.Mutex           // Mutex to ensure thread safety
+	pkgClosure Closure       = &Lifo{} // Default implementation of Closure using Lifo (Last In First Out) strategy
+	pkgTimeout time.Duration           // Default timeout of the package-level Close, zero means no timeout
+	mu         sync.Mutex              // Mutex to ensure thread safety
 	once       sync.Once
+	closing    atomic.Bool // Whether the global closure is being closed
 )
 
 // SetPackageClosure allows for setting a different Closure implementation.
-func SetPackageClosure(c Closure) {
+// The closers already registered in the previous closure are moved to the new one,
+// preserving their order, if the previous closure is a Lifo, Fifo or Group.
+//
+// Returns:
+// - The previous global closure.
+func SetPackageClosure(c Closure) Closure {
 	mu.Lock()         // Acquiring the lock
 	defer mu.Unlock() // Making sure to release the lock after the function exits
-	pkgClosure = c    // Set the global closure to the provided implementation
+
+	prev := pkgClosure
+
+	if d, ok := prev.(drainer); ok && prev != c {
+		AppendAll(c, d.drain()...) // Migrate the registered closers instead of abandoning them
+	}
+
+	pkgClosure = c // Set the global closure to the provided implementation
+
+	return prev
 }
 
 // Append appends a new closer to the global closure.
+//
+// In strict mode, see SetStrictMode, a closer neither named (see Named) nor bounded by its own timeout
+// (see AppendWithTimeout) is reported, as by Closure2.Append.
 func Append(closer Closer) {
-	mu.Lock()                 // Acquiring the lock
-	defer mu.Unlock()         // Making sure to release the lock after the function exits
+	checkStrict(packageEntry(closer, callerPackage(1)))
+	appendPackage(closer)
+}
+
+// appendPackage appends the closer to the global closure without the strict check,
+// for the helpers appending closers they name and bound themselves.
+func appendPackage(closer Closer) {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+
+	if err := checkCapacity(); err != nil {
+		// Rejected and counted, see WithMaxClosers. The closer will not be closed, so it is logged.
+		notifyLogger().Msgf("Closer %s is not appended and will not be closed: %s", describe(closer, 0).Name, err)
+		return
+	}
+
 	pkgClosure.Append(closer) // Appending the closer
 }
 
-// Close attempts to close all appended resources.
+// SetDefaultCloseTimeout sets the timeout applied by the package-level Close.
+// Zero or negative duration means no timeout.
+func SetDefaultCloseTimeout(d time.Duration) {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+	pkgTimeout = d
+}
+
+// Close attempts to close all appended resources,
+// within the default timeout if one is set with SetDefaultCloseTimeout.
 func Close() error {
-	return CloseContext(context.Background()) // Close all resources and return any encountered error
+	if err := reentrant(context.Background()); err != nil {
+		return err // Waiting for the lock would deadlock a closer calling Close
+	}
+
+	mu.Lock()
+	d := pkgTimeout
+	mu.Unlock()
+
+	return CloseWithTimeout(d) // Close all resources and return any encountered error
+}
+
+// CloseWithTimeout attempts to close all appended resources within the given duration.
+// Zero or negative duration means no timeout.
+func CloseWithTimeout(d time.Duration) error {
+	if d <= 0 {
+		return CloseContext(context.Background())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return CloseContext(ctx)
 }
 
 // CloseContext attempts to close all appended resources with context support.
+// Only the first call closes the resources, subsequent calls return nil.
+// Calls made by the closers while the resources are being closed return ErrReentrantClose,
+// naming the closer; calls from other goroutines wait until the resources are closed.
 func CloseContext(ctx context.Context) error {
+	if err := reentrant(ctx); err != nil {
+		return err // Waiting for the lock would deadlock a closer calling CloseContext
+	}
+
 	mu.Lock()         // Acquiring the lock
 	defer mu.Unlock() // Making sure to release the lock after the function exits
 
+	if err := checkDeadline(ctx); err != nil {
+		return err // Nothing is closed, so a subsequent call with a deadline can close the resources
+	}
+
 	var err error
 
 	once.Do(func() {
-		err = pkgClosure.CloseContext(ctx) // Close all resources and return any encountered error
+		started.Store(true)
+		closing.Store(true)
+		defer closing.Store(false)
+
+		ctx = ensureRunID(ctx) // Correlate all events of this shutdown run
+
+		notifyStarted(ctx)              // Notify that the shutdown has started
+		notifyShutdown(ctx, pkgClosure) // Inform the listeners before any closer is closed
+		logger := deferLogger()         // Keep the logger open until the end
+
+		report := &Report{Started: time.Now()}
+		report.RunID, _ = RunIDFromContext(ctx)
+		report.Deadline, _ = DeadlineSourceFromContext(ctx)
+		rec := &recorder{}
+		clock := watchClock(clockWatchInterval, clockAnomalyThreshold)
+
+		closeCtx := context.WithValue(context.WithValue(ctx, recorderKey{}, rec), clockWatcherKey{}, clock)
+		err = closeWithFinal(closeCtx) // Close all resources and return any encountered error
+
+		report.Duration = time.Since(report.Started)
+		report.Results = rec.seal()
+		report.Anomalies = clock.stop()
+		report.Cancelled = ctx.Err()
+		notifyCompleted(ctx, report, err) // Notify about the result
+
+		err = multierr.Append(err, flushLogs(ctx))           // Flush the logs about the shutdown itself
+		err = multierr.Append(err, closeLogger(ctx, logger)) // Close the logger absolutely last
 	})
 
 	return err
@@ -71,29 +189,44 @@ func WaitForSignals(logger Logger, sig ...os.Signal) {
 	c := make(chan os.Signal, 1)
 
 	// Register the given signals to the channel.
-	signal.Notify(c, sig...)
+	notifySignals(c, sig)
 
 	// Ensure that we stop the signal notifications to the channel when the function returns.
 	defer signal.Stop(c)
 
+	s := <-c
+	handleQuit(s)
+
 	// Log a warning when a signal is received.
-	logger.Msgf("Received signal: %s", <-c)
+	logger.Msgf("Received signal: %s", s)
+	useLogger(logger)
 }
 
 // WaitForSignalsContext is similar to WaitForSignals but with support for context.
 // It blocks until a given signal (or signals) is received or the context is done.
 func WaitForSignalsContext(ctx context.Context, logger Logger, sig ...os.Signal) {
-	// Create a context that will be done when the given signals are caught or the parent context is done.
-	sigCtx, cancel := signal.NotifyContext(ctx, sig...)
+	// Create a channel to listen for signals.
+	c := make(chan os.Signal, 1)
 
-	// Ensure resources are released when the function returns.
-	defer cancel()
+	// Register the given signals to the channel.
+	notifySignals(c, sig)
 
-	// Wait until the signal context is done (either from a caught signal or the parent context).
-	<-sigCtx.Done()
+	// Ensure that we stop the signal notifications to the channel when the function returns.
+	defer signal.Stop(c)
+
+	// Wait until a signal is caught or the parent context is done.
+	select {
+	case <-ctx.Done():
+		// Log a warning indicating the context-related error.
+		logger.Msgf("Received signal: %s", ctx.Err())
+	case s := <-c:
+		handleQuit(s)
 
-	// Log a warning indicating which signal or context-related error occurred.
-	logger.Msgf("Received signal: %s", sigCtx.Err())
+		// Log a warning when a signal is received.
+		logger.Msgf("Received signal: %s", s)
+	}
+
+	useLogger(logger)
 }
 
 type Fn func() error
@@ -102,9 +235,31 @@ func (f Fn) Close() error {
 	return f()
 }
 
+// Cleanup adapts a cleanup function without a result, such as the ones generated
+// by google/wire providers, to the Closer interface.
+type Cleanup func()
+
+// Close calls the cleanup function and always returns nil.
+func (f Cleanup) Close() error {
+	f()
+	return nil
+}
+
+// AppendCleanup appends a cleanup function to the global closure.
+// It bridges the func() cleanup convention of google/wire to this package:
+//
+//	app, cleanup, err := initializeApp()
+//	shutdown.AppendCleanup(cleanup)
+//
+// The shutdownwire module provides the closures of this package to wire graphs.
+func AppendCleanup(cleanup func()) {
+	appendPackage(Cleanup(cleanup))
+}
+
 // CloseOnSignal waits for the specified signals and then closes the global closure.
 // It utilizes the WaitForSignals function to wait for the signals.
-// Once a signal is received, it will close the global closure using the Close function.
+// Once a signal is received, it will close the global closure using the Close function,
+// within the default timeout set with SetDefaultCloseTimeout or WithCloseTimeout, if any.
 // The Logger parameter is used to log the received signal.
 //
 // Parameters:
@@ -122,7 +277,8 @@ func CloseOnSignal(logger Logger, sig ...os.Signal) error {
 // CloseOnSignalContext is similar to CloseOnSignal but with support for context.
 // It waits for the specified signals or until the context is done, then closes the global closure.
 // It utilizes the WaitForSignalsContext function to wait for the signals with context support.
-// Once a signal is received or the context is done, it will close the global closure using the CloseContext function.
+// Closing is not bound to the cancellation of ctx, since ctx is usually the trigger itself,
+// but to the default timeout, if any; use CloseOnSignalWithTimeout to set another limit.
 //
 // Parameters:
 // - ctx: The context that can be used to cancel or time out the waiting process.
@@ -132,7 +288,37 @@ func CloseOnSignal(logger Logger, sig ...os.Signal) error {
 // Returns:
 // - An error if encountered while closing the global closure; otherwise, nil.
 func CloseOnSignalContext(ctx context.Context, logger Logger, sig ...os.Signal) error {
+	mu.Lock()
+	d := pkgTimeout
+	mu.Unlock()
+
+	return CloseOnSignalWithTimeout(ctx, logger, d, sig...)
+}
+
+// CloseOnSignalWithTimeout waits for the specified signals or until the context is done,
+// then closes the global closure within a fresh deadline of d.
+// The closing context keeps the values of ctx but not its cancellation,
+// so a cancelled ctx does not immediately abort the closing.
+//
+// Parameters:
+// - ctx: The context that can be used to cancel or time out the waiting process.
+// - logger: An instance that implements the Logger interface, used for logging.
+// - d: The maximum duration of the closing; zero or negative means no limit.
+// - sig: A variable list of os.Signal values that the function should wait for.
+//
+// Returns:
+// - An error if encountered while closing the global closure; otherwise, nil.
+func CloseOnSignalWithTimeout(ctx context.Context, logger Logger, d time.Duration, sig ...os.Signal) error {
 	WaitForSignalsContext(ctx, logger, sig...)
 
-	return CloseContext(ctx)
+	closeCtx := detachContext(ctx) // Keep the values, drop the cancellation of the trigger context
+
+	if d > 0 {
+		var cancel context.CancelFunc
+
+		closeCtx, cancel = context.WithTimeout(closeCtx, d)
+		defer cancel()
+	}
+
+	return CloseContext(closeCtx)
 }
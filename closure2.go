@@ -0,0 +1,289 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCloseTimeout is returned (wrapped) when a closer does not finish within its own timeout.
+var ErrCloseTimeout = errors.New("shutdown: close timed out")
+
+// Entry describes a closer appended to a Closure2.
+type Entry struct {
+	Name     string        // Human-readable name of the closer, used in errors and reports
+	Timeout  time.Duration // Maximum close duration of the closer, zero means no own timeout
+	Priority int           // Priority of the closer, honored by strategies supporting priorities
+	Tags     []string      // Arbitrary tags of the closer
+	Package  string        // Import path of the Go package that appended the closer
+}
+
+// AppendOption configures the Entry of an appended closer.
+type AppendOption func(*Entry)
+
+// WithName sets the name of the appended closer.
+func WithName(name string) AppendOption {
+	return func(e *Entry) { e.Name = name }
+}
+
+// WithTimeout sets the maximum close duration of the appended closer.
+func WithTimeout(d time.Duration) AppendOption {
+	return func(e *Entry) { e.Timeout = d }
+}
+
+// WithPriority sets the priority of the appended closer.
+func WithPriority(priority int) AppendOption {
+	return func(e *Entry) { e.Priority = priority }
+}
+
+// WithTags adds tags to the appended closer.
+func WithTags(tags ...string) AppendOption {
+	return func(e *Entry) { e.Tags = append(e.Tags, tags...) }
+}
+
+// CloseMiddleware derives the context of a closer from the context of the close sequence,
+// e.g. to inject tenant IDs or trace baggage, or to set a deadline computed from historical durations.
+// The returned cancel function, if not nil, is called once the closer is closed.
+type CloseMiddleware func(ctx context.Context, entry Entry) (context.Context, context.CancelFunc)
+
+// Closure2 is the second version of the Closure interface.
+// Append accepts options describing the closer, and CloseContext returns a Report.
+// Use Upgrade to turn any Closure into a Closure2.
+type Closure2 interface {
+	Append(closer Closer, opts ...AppendOption)        // Appends a new closer described by the options
+	CloseContext(ctx context.Context) (*Report, error) // Closes resources and reports the outcome
+}
+
+// upgraded adapts a Closure to the Closure2 interface.
+type upgraded struct {
+	closure    Closure           // The wrapped closure
+	middleware []CloseMiddleware // Derive the context of each closer, in order
+
+	mx      sync.Mutex      // Mutex for thread safety of the fields below
+	ctx     context.Context // The context of the current close sequence
+	except  []string        // Tags of the closers kept open by the current close sequence
+	results []Result        // The results of the current close sequence
+}
+
+// Upgrade adapts the given Closure to the Closure2 interface.
+// Each appended closer is wrapped to honor its timeout and to record its Result.
+// The middleware derive the context of each closer before it is closed, in order; the context
+// is passed to the closers implementing CloseContext, and its deadline is honored for the others.
+func Upgrade(c Closure, middleware ...CloseMiddleware) Closure2 {
+	return &upgraded{closure: c, ctx: context.Background(), middleware: middleware}
+}
+
+// Append wraps the closer according to the options and appends it to the wrapped closure.
+func (u *upgraded) Append(closer Closer, opts ...AppendOption) {
+	entry := Entry{Package: callerPackage(1)}
+
+	for _, opt := range opts {
+		opt(&entry)
+	}
+
+	checkStrict(entry)
+	u.closure.Append(&entryCloser{entry: entry, closer: closer, owner: u})
+}
+
+// CloseContext closes the wrapped closure and returns the report of the close sequence.
+// Closers already closed by CloseExcept are not closed again.
+func (u *upgraded) CloseContext(ctx context.Context) (*Report, error) {
+	return u.CloseExcept(ctx)
+}
+
+// CloseExcept closes the wrapped closure, except the closers having any of the given tags,
+// and returns the report of the close sequence. The kept closers are reported as skipped
+// and are closed by a subsequent CloseContext.
+func (u *upgraded) CloseExcept(ctx context.Context, tags ...string) (*Report, error) {
+	report := &Report{Started: time.Now()}
+	report.RunID, _ = RunIDFromContext(ctx)
+	report.Deadline, _ = DeadlineSourceFromContext(ctx)
+
+	u.mx.Lock()
+	u.ctx = ctx
+	u.except = tags
+	u.results = nil
+	u.mx.Unlock()
+
+	clock := watchClock(clockWatchInterval, clockAnomalyThreshold)
+	err := u.closure.CloseContext(context.WithValue(ctx, clockWatcherKey{}, clock))
+	report.Anomalies = clock.stop()
+	report.Cancelled = ctx.Err()
+
+	u.mx.Lock()
+	report.Duration = time.Since(report.Started)
+	report.Results = u.results
+	u.mx.Unlock()
+
+	return report, err
+}
+
+// closeContext returns the context of the given closer in the current close sequence,
+// derived by the middleware, and the function releasing it.
+func (u *upgraded) closeContext(entry Entry) (context.Context, func()) {
+	u.mx.Lock()
+	ctx := u.ctx
+	u.mx.Unlock()
+
+	var cancels []context.CancelFunc
+
+	for _, mw := range u.middleware {
+		var cancel context.CancelFunc
+
+		ctx, cancel = mw(ctx, entry)
+		if cancel != nil {
+			cancels = append(cancels, cancel)
+		}
+	}
+
+	return ctx, func() {
+		for i := len(cancels) - 1; i >= 0; i-- {
+			cancels[i]()
+		}
+	}
+}
+
+// kept reports whether a closer with the given tags is kept open by the current close sequence.
+func (u *upgraded) kept(tags []string) bool {
+	u.mx.Lock()
+	defer u.mx.Unlock()
+
+	for _, tag := range tags {
+		for _, except := range u.except {
+			if tag == except {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// record stores the result of a closed closer.
+func (u *upgraded) record(result Result) {
+	u.mx.Lock()
+	defer u.mx.Unlock()
+
+	u.results = append(u.results, result)
+}
+
+// entryCloser is a closer appended through a Closure2, carrying its Entry.
+type entryCloser struct {
+	entry  Entry       // The description of the closer
+	closer Closer      // The wrapped closer
+	owner  *upgraded   // The closure recording the results
+	closed atomic.Bool // Whether the closer has already been closed
+}
+
+// Close closes the wrapped closer within its timeout and records the result.
+func (e *entryCloser) Close() error {
+	if e.closed.Load() {
+		return nil // Already closed by a previous (partial) close sequence
+	}
+
+	if e.owner.kept(e.entry.Tags) {
+		e.owner.record(Result{Entry: e.entry, Skipped: true})
+		return nil
+	}
+
+	skip := decideSkip(e.closer)
+	if skip.skip {
+		e.owner.record(Result{Entry: e.entry, Skipped: true})
+		return nil
+	}
+
+	e.closed.Store(true)
+
+	ctx, release := e.owner.closeContext(e.entry)
+	defer release()
+
+	ctx = withSkipDecision(ctx, skip) // Do not evaluate the predicate again
+
+	start := time.Now()
+	err := closeWithTimeout(ctx, e.closer, e.entry.Timeout)
+
+	if err != nil && e.entry.Name != "" {
+		err = fmt.Errorf("%s: %w", e.entry.Name, err)
+	}
+
+	e.owner.record(Result{Entry: e.entry, Duration: time.Since(start), Err: err})
+
+	return err
+}
+
+// closeCtx closes the closer with the context if it supports one.
+func closeCtx(ctx context.Context, closer Closer) error {
+	if c, ok := closer.(ContextCloser); ok {
+		return c.CloseContext(ctx)
+	}
+
+	return closer.Close()
+}
+
+// closeWithTimeout closes the closer, giving up once the timeout expires or the context is done.
+// Zero or negative timeout means the closer is bound by the context only.
+func closeWithTimeout(ctx context.Context, closer Closer, timeout time.Duration) error {
+	if timeout <= 0 {
+		if ctx.Done() == nil {
+			return closeCtx(ctx, closer) // Never done, no need to watch it
+		}
+	} else {
+		var cancel context.CancelFunc
+
+		ctx, cancel = withBudget(ctx, timeout) // Extended by the pauses of the process
+		defer cancel()
+	}
+
+	done := make(chan error, 1) // Buffered, so the goroutine never leaks on timeout
+
+	go func() {
+		defer markGoroutine(ctx)() // A reentrant Close is recognized on this goroutine too
+
+		done <- closeWithPolicy(ctx, closer, policyFromContext(ctx)) // A panic would not be recovered by the caller
+	}()
+
+	select {
+	case <-ctx.Done(): // If the timeout expires or the parent context is done.
+		if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s", ErrCloseTimeout, timeout)
+		}
+
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// downgraded adapts a Closure2 to the Closure interface.
+type downgraded struct {
+	closure Closure2 // The wrapped closure
+}
+
+// Downgrade adapts the given Closure2 to the Closure interface, discarding the reports.
+func Downgrade(c Closure2) Closure {
+	return &downgraded{closure: c}
+}
+
+// Append appends the closer to the wrapped closure without options.
+func (d *downgraded) Append(closer Closer) {
+	d.closure.Append(closer)
+}
+
+// CloseContext closes the wrapped closure, discarding the report.
+func (d *downgraded) CloseContext(ctx context.Context) error {
+	_, err := d.closure.CloseContext(ctx)
+	return err
+}
+
+// Close closes the wrapped closure without context support.
+func (d *downgraded) Close() error {
+	return d.CloseContext(context.Background())
+}
+
+// WithContext associates the downgraded closure with the given context.
+func (d *downgraded) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, d)
+}
@@ -0,0 +1,140 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgrade(t *testing.T) {
+	closure := Upgrade(&Lifo{})
+
+	closure.Append(&mockCloser{}, WithName("db"), WithTags("storage"))
+	closure.Append(&mockCloser{closeFunc: func() error {
+		return errors.New("close error")
+	}}, WithName("cache"))
+	closure.Append(&mockCloser{closeFunc: func() error {
+		time.Sleep(time.Second)
+		return nil
+	}}, WithName("http"), WithTimeout(20*time.Millisecond))
+
+	ctx := RunIDToContext(context.Background(), "run-1")
+
+	report, err := closure.CloseContext(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCloseTimeout)
+	assert.Contains(t, err.Error(), "cache: close error")
+	assert.Contains(t, err.Error(), "http: shutdown: close timed out after 20ms")
+
+	assert.Equal(t, "run-1", report.RunID)
+	assert.GreaterOrEqual(t, report.Duration, 20*time.Millisecond)
+
+	if assert.Len(t, report.Results, 3) {
+		assert.Equal(t, "http", report.Results[0].Name)
+		assert.ErrorIs(t, report.Results[0].Err, ErrCloseTimeout)
+		assert.Equal(t, "cache", report.Results[1].Name)
+		assert.Error(t, report.Results[1].Err)
+		assert.Equal(t, "db", report.Results[2].Name)
+		assert.Equal(t, []string{"storage"}, report.Results[2].Tags)
+		assert.NoError(t, report.Results[2].Err)
+	}
+}
+
+func TestDowngrade(t *testing.T) {
+	closure := Downgrade(Upgrade(&Fifo{}))
+
+	closed := false
+	closure.Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	assert.NoError(t, closure.Close())
+	assert.True(t, closed)
+
+	extracted, ok := ClosureFromContext(closure.WithContext(context.Background()))
+	assert.True(t, ok)
+	assert.Equal(t, closure, extracted)
+}
+
+// tenantKey is the context key of the tenant injected by the test middleware.
+type tenantKey struct{}
+
+// ctxCloser records the context it is closed with.
+type ctxCloser struct {
+	ctx context.Context
+}
+
+func (c *ctxCloser) CloseContext(ctx context.Context) error {
+	c.ctx = ctx
+	return nil
+}
+
+func (c *ctxCloser) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+func TestUpgrade_Middleware(t *testing.T) {
+	released := 0
+
+	tenant := func(ctx context.Context, entry Entry) (context.Context, context.CancelFunc) {
+		return context.WithValue(ctx, tenantKey{}, "tenant-"+entry.Name), nil
+	}
+	deadline := func(ctx context.Context, entry Entry) (context.Context, context.CancelFunc) {
+		if entry.Name != "slow" {
+			return ctx, nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+
+		return ctx, func() {
+			released++
+			cancel()
+		}
+	}
+
+	closure := Upgrade(&Fifo{}, tenant, deadline)
+
+	fast := &ctxCloser{}
+	closure.Append(fast, WithName("fast"))
+	closure.Append(Fn(func() error {
+		time.Sleep(time.Second)
+		return nil
+	}), WithName("slow"))
+
+	report, err := closure.CloseContext(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, ErrCloseTimeout)
+	assert.Equal(t, "tenant-fast", fast.ctx.Value(tenantKey{}))
+	assert.Equal(t, 1, released)
+
+	if assert.Len(t, report.Results, 2) {
+		assert.NoError(t, report.Results[0].Err)
+		assert.ErrorIs(t, report.Results[1].Err, context.DeadlineExceeded)
+	}
+}
+
+func TestUpgrade_Panic(t *testing.T) {
+	closure := Upgrade(&Fifo{})
+
+	closure.Append(Fn(func() error { panic("boom") }), WithName("db"))
+	closure.Append(&mockCloser{}, WithName("cache"))
+
+	// The deadline makes the entries close in a goroutine watching the context.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	report, err := closure.CloseContext(ctx)
+
+	var perr *PanicError
+	assert.ErrorAs(t, err, &perr)
+
+	if assert.Len(t, report.Results, 2) {
+		assert.ErrorAs(t, report.Results[0].Err, &perr)
+		assert.NoError(t, report.Results[1].Err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type pkgCloser struct {
@@ -26,9 +27,24 @@ func (mc *pkgCloser) Close() error {
 	return mc.err
 }
 
-func TestAppendAndClose(t *testing.T) {
-	SetPackageClosure(&Lifo{})
+// resetPackage replaces the global closure without migrating the registered closers,
+// and allows closing it again.
+func resetPackage(c Closure) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	pkgClosure = c
+	pkgLogger = nil
 	once = sync.Once{}
+	started.Store(false)
+
+	runningMx.Lock()
+	running = nil // Forget the closers abandoned by the previous tests
+	runningMx.Unlock()
+}
+
+func TestAppendAndClose(t *testing.T) {
+	resetPackage(&Lifo{})
 	mCloser := &pkgCloser{}
 	Append(mCloser)
 	if err := Close(); err != nil || !mCloser.isClose {
@@ -37,8 +53,7 @@ func TestAppendAndClose(t *testing.T) {
 }
 
 func TestAppendAndCloseWithError(t *testing.T) {
-	SetPackageClosure(&Fifo{})
-	once = sync.Once{}
+	resetPackage(&Fifo{})
 	expectedErr := errors.New("close error")
 	mCloser := &pkgCloser{err: expectedErr}
 	Append(mCloser)
@@ -99,6 +114,31 @@ func TestFn_Close(t *testing.T) {
 	}
 }
 
+func TestCloseReentrant(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	var reentrantErr error
+
+	Append(Fn(func() error {
+		reentrantErr = Close()
+		return nil
+	}))
+
+	assert.NoError(t, Close())
+	assert.ErrorIs(t, reentrantErr, ErrReentrantClose)
+	assert.NoError(t, Close()) // The shutdown is over, subsequent calls are no-op
+}
+
+func TestAppendCleanup(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	called := false
+	AppendCleanup(func() { called = true })
+
+	assert.NoError(t, Close())
+	assert.True(t, called)
+}
+
 func getLastLoggedMessage(ml *mockLogger) string {
 	ml.mu.Lock()
 	defer ml.mu.Unlock()
@@ -142,3 +182,187 @@ func TestCloseOnSignalContextCancelled(t *testing.T) {
 
 	assert.Equal(t, "Received signal: context canceled", getLastLoggedMessage(logger))
 }
+
+func TestCloseOnSignalContext_ClosesAfterCancel(t *testing.T) {
+	resetPackage(&Lifo{})
+	logger := &mockLogger{}
+
+	mCloser := &pkgCloser{}
+	Append(Fn(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return mCloser.Close()
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The cancelled trigger context must not abort the closing.
+	err := CloseOnSignalContext(ctx, logger, os.Interrupt)
+	assert.NoError(t, err)
+	assert.True(t, mCloser.isClose)
+}
+
+func TestCloseOnSignalContext_DefaultTimeout(t *testing.T) {
+	resetPackage(&Lifo{})
+	SetDefaultCloseTimeout(20 * time.Millisecond)
+
+	defer SetDefaultCloseTimeout(0)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	Append(Fn(func() error {
+		<-release
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := CloseOnSignalContext(ctx, &mockLogger{}, os.Interrupt)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCloseOnSignalWithTimeout(t *testing.T) {
+	resetPackage(&Lifo{})
+	logger := &mockLogger{}
+
+	Append(Fn(func() error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := CloseOnSignalWithTimeout(ctx, logger, 20*time.Millisecond, os.Interrupt)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "Received signal: context canceled", getLastLoggedMessage(logger))
+}
+
+func TestCloseWithTimeout(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	Append(Fn(func() error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+
+	err := CloseWithTimeout(20 * time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSetDefaultCloseTimeout(t *testing.T) {
+	resetPackage(&Lifo{})
+	SetDefaultCloseTimeout(20 * time.Millisecond)
+	defer SetDefaultCloseTimeout(0)
+
+	Append(Fn(func() error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+
+	err := Close()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestContextCloser(t *testing.T) {
+	type key struct{}
+
+	ctx := context.WithValue(context.Background(), key{}, "shutdown")
+
+	for name, closure := range map[string]Closure{
+		"lifo": &Lifo{}, "fifo": &Fifo{}, "group": &Group{}, "priority": &Priority{}, "dag": &Dag{},
+	} {
+		direct, named, skip, nested := &ctxCloser{}, &ctxCloser{}, &ctxCloser{}, &ctxCloser{}
+
+		inner := &Fifo{}
+		inner.Append(nested)
+
+		closure.Append(direct)
+		closure.Append(Named("named", named))
+		closure.Append(SkipIf(func() bool { return false }, WithPanicPolicy(PanicContinue, skip)))
+		closure.Append(inner)
+
+		require.NoError(t, closure.CloseContext(ctx), name)
+
+		for _, c := range []*ctxCloser{direct, named, skip, nested} {
+			require.NotNil(t, c.ctx, name)
+			assert.Equal(t, "shutdown", c.ctx.Value(key{}), name)
+		}
+	}
+}
+
+func TestCloseReentrant_Named(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	var closeErr, closeCtxErr error
+
+	Append(Named("close", Fn(func() error {
+		closeErr = Close()
+		return nil
+	})))
+	Append(Named("close-context", &ctxCloserFunc{fn: func(ctx context.Context) error {
+		closeCtxErr = CloseContext(ctx)
+		return nil
+	}}))
+
+	assert.NoError(t, Close())
+	assert.ErrorIs(t, closeErr, ErrReentrantClose)
+	assert.EqualError(t, closeErr, "shutdown: reentrant close by closer close")
+	assert.ErrorIs(t, closeCtxErr, ErrReentrantClose)
+	assert.EqualError(t, closeCtxErr, "shutdown: reentrant close by closer close-context")
+}
+
+func TestCloseReentrant_Timeout(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	var reentrantErr error
+
+	// The closer having its own timeout runs on another goroutine.
+	AppendWithTimeout(Named("timed", Fn(func() error {
+		reentrantErr = Close()
+		return nil
+	})), time.Second)
+
+	start := time.Now()
+	assert.NoError(t, Close())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.EqualError(t, reentrantErr, "shutdown: reentrant close by closer timed")
+}
+
+func TestClose_Concurrent(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	started := make(chan struct{})
+	closed := false
+
+	Append(Fn(func() error {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		closed = true
+
+		return nil
+	}))
+
+	go func() { _ = Close() }()
+
+	<-started
+
+	// A concurrent caller waits for the running shutdown instead of failing.
+	assert.NoError(t, Close())
+	assert.True(t, closed)
+}
+
+// ctxCloserFunc adapts a function to the ContextCloser interface.
+type ctxCloserFunc struct {
+	fn func(ctx context.Context) error
+}
+
+func (c *ctxCloserFunc) CloseContext(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+func (c *ctxCloserFunc) Close() error {
+	return c.fn(context.Background())
+}
@@ -0,0 +1,32 @@
+package shutdown
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// AppendSlice appends the items of a dynamic collection to the global closure, in slice order.
+func AppendSlice[T io.Closer](items []T) {
+	for _, item := range items {
+		appendPackage(item)
+	}
+}
+
+// AppendMap appends the items of a keyed collection, e.g. connections per tenant or shard,
+// to the global closure. Since the map iteration order is random, the items are appended
+// in the order of their keys defined by less; each closer is named after its key.
+func AppendMap[K comparable, T io.Closer](m map[K]T, less func(a, b K) bool) {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return less(keys[i], keys[j])
+	})
+
+	for _, key := range keys {
+		appendPackage(Named(fmt.Sprint(key), m[key]))
+	}
+}
@@ -0,0 +1,37 @@
+package shutdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendSlice(t *testing.T) {
+	resetPackage(&Fifo{})
+
+	var order []string
+
+	AppendSlice([]*recordCloser{
+		{name: "first", order: &order},
+		{name: "second", order: &order},
+	})
+
+	assert.NoError(t, Close())
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestAppendMap(t *testing.T) {
+	resetPackage(&Fifo{})
+
+	var order []string
+
+	shards := map[int]*recordCloser{}
+	for i, name := range []string{"zero", "one", "two", "three"} {
+		shards[i] = &recordCloser{name: name, order: &order}
+	}
+
+	AppendMap(shards, func(a, b int) bool { return a > b })
+
+	assert.NoError(t, Close())
+	assert.Equal(t, []string{"three", "two", "one", "zero"}, order)
+}
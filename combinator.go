@@ -0,0 +1,93 @@
+package shutdown
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// triggerResult is the outcome of waiting for a single trigger.
+type triggerResult struct {
+	cause string
+	err   error
+}
+
+// AnyTrigger returns a trigger that fires as soon as any of the given triggers fires.
+// The other triggers stop waiting once the first one returns.
+func AnyTrigger(triggers ...Trigger) Trigger {
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel() // Stop waiting for the other triggers
+
+		results := make(chan triggerResult, len(triggers))
+
+		for _, t := range triggers {
+			go func(t Trigger) {
+				cause, err := t.Wait(ctx)
+				results <- triggerResult{cause: cause, err: err}
+			}(t)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case res := <-results:
+			return res.cause, res.err
+		}
+	})
+}
+
+// AllTriggers returns a trigger that fires once all the given triggers have fired.
+// If any trigger returns an error, the others stop waiting and the error is returned.
+func AllTriggers(triggers ...Trigger) Trigger {
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel() // Stop waiting for the other triggers on error
+
+		results := make(chan triggerResult, len(triggers))
+
+		for _, t := range triggers {
+			go func(t Trigger) {
+				cause, err := t.Wait(ctx)
+				results <- triggerResult{cause: cause, err: err}
+			}(t)
+		}
+
+		causes := make([]string, 0, len(triggers))
+
+		for range triggers {
+			res := <-results
+			if res.err != nil {
+				return "", res.err
+			}
+
+			causes = append(causes, res.cause)
+		}
+
+		return strings.Join(causes, " and "), nil
+	})
+}
+
+// Debounce returns a trigger that fires d after the given trigger first fired, so that a burst of
+// events, such as repeated signals or a flapping check, results in a single decision taken once the
+// burst has settled. The period is measured from the first event only: the given trigger is not
+// waited for again, so edge-triggered triggers, such as signals, fire as well as level-triggered ones.
+// Every call to Wait starts over.
+func Debounce(trigger Trigger, d time.Duration) Trigger {
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		cause, err := trigger.Wait(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timer.C:
+			return cause + " (debounced " + d.String() + ")", nil
+		}
+	})
+}
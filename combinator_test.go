@@ -0,0 +1,69 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func delayedTrigger(d time.Duration, cause string) Trigger {
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(d):
+			return cause, nil
+		}
+	})
+}
+
+func TestAnyTrigger(t *testing.T) {
+	cause, err := AnyTrigger(delayedTrigger(time.Second, "slow"), delayedTrigger(10*time.Millisecond, "fast")).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "fast", cause)
+}
+
+func TestAllTriggers(t *testing.T) {
+	cause, err := AllTriggers(delayedTrigger(20*time.Millisecond, "slow"), delayedTrigger(10*time.Millisecond, "fast")).
+		Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "fast and slow", cause)
+
+	expectedErr := errors.New("trigger error")
+	failing := TriggerFunc(func(context.Context) (string, error) { return "", expectedErr })
+
+	_, err = AllTriggers(delayedTrigger(time.Second, "slow"), failing).Wait(context.Background())
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestDebounce(t *testing.T) {
+	fired := make(chan struct{})
+
+	// A one-shot trigger fires once, and blocks if waited for again.
+	oneShot := TriggerFunc(func(ctx context.Context) (string, error) {
+		select {
+		case <-fired:
+			<-ctx.Done()
+			return "", ctx.Err()
+		default:
+			close(fired)
+			return "signal", nil
+		}
+	})
+
+	start := time.Now()
+	cause, err := Debounce(oneShot, 20*time.Millisecond).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "signal (debounced 20ms)", cause)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = Debounce(delayedTrigger(0, "fast"), time.Second).Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
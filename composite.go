@@ -0,0 +1,32 @@
+package shutdown
+
+// ParallelPhase returns a single composite closer that closes all the given closers concurrently.
+// It allows inserting a parallel batch anywhere within an otherwise sequential registration:
+//
+//	lifo.Append(db)
+//	lifo.Append(ParallelPhase(httpServer, grpcServer, metricsServer))
+func ParallelPhase(closers ...Closer) Closer {
+	return BoundedPhase(0, closers...)
+}
+
+// BoundedPhase is ParallelPhase closing at most limit closers at the same time, as a Group built
+// with WithConcurrency does, e.g. to avoid hammering a shared dependency; zero or negative means no limit:
+//
+//	lifo.Append(BoundedPhase(4, tenantPools...))
+func BoundedPhase(limit int, closers ...Closer) Closer {
+	g := &Group{concurrency: limit}
+	AppendAll(g, closers...)
+
+	return g
+}
+
+// Sequence returns a single composite closer that closes the given closers strictly in order,
+// sharing one deadline. It allows registering a multi-step teardown as one unit:
+//
+//	lifo.Append(Sequence(flusher, file, tempDir))
+func Sequence(closers ...Closer) Closer {
+	f := &Fifo{}
+	AppendAll(f, closers...)
+
+	return f
+}
@@ -0,0 +1,81 @@
+package shutdown
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelPhase(t *testing.T) {
+	c1 := &groupCloser{delay: 30 * time.Millisecond}
+	c2 := &groupCloser{delay: 30 * time.Millisecond, err: errors.New("closer error")}
+
+	var order []string
+
+	lifo := &Lifo{}
+	lifo.Append(Fn(func() error {
+		order = append(order, "first")
+		return nil
+	}))
+	lifo.Append(ParallelPhase(c1, c2))
+	lifo.Append(Fn(func() error {
+		order = append(order, "last")
+		return nil
+	}))
+
+	start := time.Now()
+	err := lifo.Close()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "closer error")
+	assert.Less(t, time.Since(start), 60*time.Millisecond) // Closed concurrently
+	assert.Equal(t, int32(1), atomic.LoadInt32(&c1.calls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&c2.calls))
+	assert.Equal(t, []string{"last", "first"}, order)
+}
+
+func TestBoundedPhase(t *testing.T) {
+	var running, peak atomic.Int32
+
+	closers := make([]Closer, 6)
+	for i := range closers {
+		closers[i] = Fn(func() error {
+			n := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			return nil
+		})
+	}
+
+	assert.NoError(t, BoundedPhase(2, closers...).Close())
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestSequence(t *testing.T) {
+	var order []string
+
+	step := func(name string, err error) Closer {
+		return Fn(func() error {
+			order = append(order, name)
+			return err
+		})
+	}
+
+	err := Sequence(step("flush", nil), step("close", errors.New("close error")), step("delete", nil)).Close()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "close error")
+	assert.Equal(t, []string{"flush", "close", "delete"}, order)
+}
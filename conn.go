@@ -0,0 +1,142 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// ConnRegistry tracks accepted connections of a generic server. On shutdown it sets their
+// read/write deadlines to now+grace to unblock blocked Reads, gives the handlers another grace
+// period to close them, and then closes the stragglers.
+type ConnRegistry struct {
+	grace time.Duration         // Time given to the handlers before and after the deadlines
+	conns map[net.Conn]struct{} // The tracked connections
+	empty chan struct{}         // Closed once the registry becomes empty during the shutdown
+	mx    sync.Mutex            // Mutex for thread safety
+}
+
+// NewConnRegistry creates a new ConnRegistry with the given grace period.
+func NewConnRegistry(grace time.Duration) *ConnRegistry {
+	return &ConnRegistry{grace: grace, conns: make(map[net.Conn]struct{})}
+}
+
+// Track registers the connection and returns a connection that is untracked when closed.
+func (r *ConnRegistry) Track(conn net.Conn) net.Conn {
+	r.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer r.mx.Unlock() // Release the lock after the function finishes.
+	r.conns[conn] = struct{}{}
+
+	return &trackedConn{Conn: conn, registry: r}
+}
+
+// Listener returns a listener tracking all accepted connections in the registry.
+func (r *ConnRegistry) Listener(l net.Listener) net.Listener {
+	return &trackingListener{Listener: l, registry: r}
+}
+
+// Len returns the number of tracked connections.
+func (r *ConnRegistry) Len() int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return len(r.conns)
+}
+
+// untrack removes the connection from the registry.
+func (r *ConnRegistry) untrack(conn net.Conn) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	delete(r.conns, conn)
+
+	if len(r.conns) == 0 && r.empty != nil {
+		close(r.empty)
+		r.empty = nil
+	}
+}
+
+// CloseContext sets the deadlines of the tracked connections to now+grace, waits until
+// the handlers close them, another grace period passes after the deadlines, or the context is done,
+// and then closes the remaining connections.
+func (r *ConnRegistry) CloseContext(ctx context.Context) error {
+	deadline := time.Now().Add(r.grace)
+
+	r.mx.Lock()
+
+	var errs error
+
+	for conn := range r.conns {
+		errs = multierr.Append(errs, conn.SetDeadline(deadline)) // Unblock the blocked Reads and Writes
+	}
+
+	empty := make(chan struct{})
+	if len(r.conns) == 0 {
+		close(empty)
+	} else {
+		r.empty = empty
+	}
+
+	r.mx.Unlock()
+
+	timer := time.NewTimer(2 * r.grace) // Let the handlers react to the expired deadlines
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out.
+	case <-timer.C: // The grace period has expired.
+	case <-empty: // All connections are closed by their handlers.
+		return errs
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	for conn := range r.conns {
+		errs = multierr.Append(errs, conn.Close()) // Close the stragglers
+		delete(r.conns, conn)
+	}
+
+	r.empty = nil
+
+	return errs
+}
+
+// Close closes the tracked connections after the grace period.
+func (r *ConnRegistry) Close() error {
+	return r.CloseContext(context.Background())
+}
+
+// trackedConn is a connection untracked from its registry when closed.
+type trackedConn struct {
+	net.Conn
+	registry *ConnRegistry
+	once     sync.Once
+}
+
+// Close closes the connection and untracks it.
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() { c.registry.untrack(c.Conn) })
+
+	return err
+}
+
+// trackingListener tracks the accepted connections in its registry.
+type trackingListener struct {
+	net.Listener
+	registry *ConnRegistry
+}
+
+// Accept accepts a connection and tracks it.
+func (l *trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return l.registry.Track(conn), nil
+}
@@ -0,0 +1,65 @@
+package shutdown
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnRegistry(t *testing.T) {
+	registry := NewConnRegistry(time.Second)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	listener := registry.Listener(l)
+	handled := make(chan error, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			handled <- err
+			return
+		}
+
+		// A handler blocked in Read, unblocked by the deadline, closing the connection.
+		_, err = conn.Read(make([]byte, 1))
+		handled <- err
+		_ = conn.Close()
+	}()
+
+	client, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Eventually(t, func() bool { return registry.Len() == 1 }, time.Second, time.Millisecond)
+
+	registry.grace = 20 * time.Millisecond
+	start := time.Now()
+	assert.NoError(t, registry.Close())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Zero(t, registry.Len())
+
+	var netErr net.Error
+	if assert.ErrorAs(t, <-handled, &netErr) {
+		assert.True(t, netErr.Timeout())
+	}
+}
+
+func TestConnRegistry_Stragglers(t *testing.T) {
+	registry := NewConnRegistry(10 * time.Millisecond)
+
+	server, client := net.Pipe()
+	defer client.Close()
+
+	registry.Track(server)
+	assert.NoError(t, registry.Close())
+	assert.Zero(t, registry.Len())
+
+	_, err := server.Write([]byte("x"))
+	assert.Error(t, err) // The straggler is closed
+}
@@ -1,11 +1,21 @@
 package shutdown
 
-import "context"
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
 
 // ctxKey is a private struct used as a unique key for storing
 // and retrieving the Closure value in the context.
 type ctxKey struct{}
 
+// runIDKey is a private struct used as a unique key for storing
+// and retrieving the shutdown run ID in the context.
+type runIDKey struct{}
+
 // ClosureToContext associates the provided Closure with the given context
 // and returns a new context with that association.
 func ClosureToContext(ctx context.Context, closure Closure) context.Context {
@@ -18,3 +28,100 @@ func ClosureFromContext(ctx context.Context) (Closure, bool) {
 	closure, ok := ctx.Value(ctxKey{}).(Closure)
 	return closure, ok
 }
+
+// typedKey is a private struct used as a unique key for storing
+// and retrieving a closure of type T in the context.
+type typedKey[T Closure] struct{}
+
+// ToContext associates the closure with the context under a key of its own type,
+// so a specialized closure is retrieved with FromContext without a type assertion,
+// independently of the closure associated with ClosureToContext.
+func ToContext[T Closure](ctx context.Context, closure T) context.Context {
+	return context.WithValue(ctx, typedKey[T]{}, closure)
+}
+
+// FromContext retrieves the closure of type T associated with the context by ToContext,
+// or else by ClosureToContext if that closure is of type T, e.g. by the WithContext methods.
+// It returns the closure and a boolean indicating if it was found.
+func FromContext[T Closure](ctx context.Context) (T, bool) {
+	if closure, ok := ctx.Value(typedKey[T]{}).(T); ok {
+		return closure, true
+	}
+
+	closure, ok := ctx.Value(ctxKey{}).(T)
+
+	return closure, ok
+}
+
+// RunIDToContext associates the given shutdown run ID with the context.
+// It can be used to correlate a shutdown with an ID generated outside of this package.
+func RunIDToContext(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+// RunIDFromContext retrieves the shutdown run ID associated with the given context.
+// The package-level CloseContext associates a run ID with the context passed
+// to the notifiers, so logs and events of one shutdown attempt can be correlated.
+func RunIDFromContext(ctx context.Context) (string, bool) {
+	runID, ok := ctx.Value(runIDKey{}).(string)
+	return runID, ok
+}
+
+// ensureRunID returns ctx if it already carries a run ID,
+// otherwise it returns a new context with a freshly generated run ID.
+func ensureRunID(ctx context.Context) context.Context {
+	if _, ok := RunIDFromContext(ctx); ok {
+		return ctx
+	}
+
+	return RunIDToContext(ctx, newRunID())
+}
+
+// runLogger is a Logger prefixing the messages with the run ID of the shutdown.
+type runLogger struct {
+	logger Logger // The wrapped logger
+	prefix string // The prefix of the messages, a format with escaped verbs
+}
+
+// withRunID returns the logger prefixing the messages with the run ID carried by ctx, e.g. "[run 1a2b...] ",
+// so the logs of one shutdown attempt can be correlated; it returns logger as is if ctx has no run ID.
+func withRunID(ctx context.Context, logger Logger) Logger {
+	runID, ok := RunIDFromContext(ctx)
+	if !ok {
+		return logger
+	}
+
+	return runLogger{logger: logger, prefix: "[run " + strings.ReplaceAll(runID, "%", "%%") + "] "}
+}
+
+// Msgf logs the message with the run ID prefix.
+func (l runLogger) Msgf(format string, args ...interface{}) {
+	l.logger.Msgf(l.prefix+format, args...)
+}
+
+// newRunID generates a random 128-bit run ID in hex encoding.
+func newRunID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read never fails on supported platforms
+
+	return hex.EncodeToString(b)
+}
+
+// detachedContext is a context that keeps the values of its parent
+// but is never cancelled and has no deadline.
+type detachedContext struct {
+	parent context.Context
+}
+
+// detachContext returns a context that carries the values of ctx but not its cancellation.
+func detachContext(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
+
+func (d detachedContext) Value(key any) any { return d.parent.Value(key) }
@@ -23,3 +23,70 @@ func TestClosureFromContext_NoClosure(t *testing.T) {
 		t.Fatalf("Expected no closure in context, but got %v", extractedClosure)
 	}
 }
+
+func TestDetachContext(t *testing.T) {
+	closure := &Lifo{}
+	ctx, cancel := context.WithCancel(ClosureToContext(context.Background(), closure))
+	cancel()
+
+	detached := detachContext(ctx)
+	if detached.Err() != nil {
+		t.Fatalf("Expected detached context not to be cancelled, got %v", detached.Err())
+	}
+
+	if extracted, ok := ClosureFromContext(detached); !ok || extracted != closure {
+		t.Fatalf("Expected detached context to keep the values of its parent")
+	}
+}
+
+func TestRunIDContext(t *testing.T) {
+	if _, ok := RunIDFromContext(context.Background()); ok {
+		t.Fatalf("Expected no run ID in an empty context")
+	}
+
+	ctx := ensureRunID(context.Background())
+
+	runID, ok := RunIDFromContext(ctx)
+	if !ok || len(runID) != 32 {
+		t.Fatalf("Expected a generated run ID, got %q", runID)
+	}
+
+	if again, _ := RunIDFromContext(ensureRunID(ctx)); again != runID {
+		t.Fatalf("Expected the existing run ID %q to be kept, got %q", runID, again)
+	}
+}
+
+func TestTypedContext(t *testing.T) {
+	lifo, priority := &Lifo{}, &Priority{}
+
+	ctx := ToContext(ClosureToContext(context.Background(), lifo), priority)
+
+	if extracted, ok := FromContext[*Priority](ctx); !ok || extracted != priority {
+		t.Fatalf("Expected the Priority to be retrieved by its type")
+	}
+
+	if extracted, ok := FromContext[*Lifo](ctx); !ok || extracted != lifo {
+		t.Fatalf("Expected the Lifo associated with ClosureToContext to be retrieved by its type")
+	}
+
+	if _, ok := FromContext[*Fifo](ctx); ok {
+		t.Fatalf("Expected no Fifo in the context")
+	}
+
+	if extracted, ok := ClosureFromContext(ctx); !ok || extracted != lifo {
+		t.Fatalf("Expected ToContext not to replace the closure of ClosureToContext")
+	}
+}
+
+func TestWithRunID(t *testing.T) {
+	logger := &mockLogger{}
+	if withRunID(context.Background(), logger) != Logger(logger) {
+		t.Fatal("Expected the logger to be kept without a run ID")
+	}
+
+	withRunID(RunIDToContext(context.Background(), "100%"), logger).Msgf("closing %s", "db")
+
+	if msg := getLastLoggedMessage(logger); msg != "[run 100%] closing db" {
+		t.Fatalf("Expected the message prefixed with the run ID, got %q", msg)
+	}
+}
@@ -0,0 +1,214 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// ErrDependencyCycle is returned by Node.DependsOn when the dependency would form a cycle.
+var ErrDependencyCycle = errors.New("shutdown: dependency cycle")
+
+// Dag represents a graph of resources closed in dependency order: a closer is closed before
+// the closers it depends on, e.g. the HTTP server before the database pool it queries,
+// and the independent branches are closed concurrently.
+type Dag struct {
+	progress
+
+	nodes   []*Node       // The nodes of the graph, in the order of appending.
+	mx      sync.Mutex    // Mutex for thread safety.
+	timeout time.Duration // Timeout of Close, zero means no timeout.
+}
+
+// Node identifies a closer appended to a Dag, so that dependencies can be declared on it.
+type Node struct {
+	closer Closer  // The closer of the node.
+	deps   []*Node // The nodes closed after this one.
+	dag    *Dag    // The graph of the node.
+}
+
+// NewDag returns a Dag configured by the options; WithCloseTimeout sets the timeout of Close.
+// The zero Dag is ready to use without options.
+func NewDag(opts ...Option) (*Dag, error) {
+	o, err := newOptions(targetDag, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dag{timeout: o.timeout}, nil
+}
+
+// Append adds a new closer without dependencies to the graph.
+func (d *Dag) Append(closer Closer) {
+	d.Add(closer)
+}
+
+// Add adds a new closer depending on the closers of the given nodes, which are closed after it:
+//
+//	db := dag.Add(pool)
+//	dag.Add(server, db)
+//
+// Nodes of other graphs are ignored. Since a node can only depend on already added nodes,
+// the dependencies of Add never form a cycle.
+//
+// Returns:
+// - The node of the added closer, to declare further dependencies.
+func (d *Dag) Add(closer Closer, dependsOn ...*Node) *Node {
+	d.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer d.mx.Unlock() // Release the lock after the function finishes.
+
+	n := &Node{closer: closer, dag: d}
+
+	for _, dep := range dependsOn {
+		if dep.dag == d {
+			n.deps = append(n.deps, dep)
+		}
+	}
+
+	d.nodes = append(d.nodes, n)
+	d.added()
+
+	return n
+}
+
+// DependsOn declares that the node depends on the given nodes, which are closed after it,
+// e.g. when the dependency is added after the dependent.
+// It returns ErrDependencyCycle, declaring none of the dependencies, if one of them would form a cycle.
+// Nodes of other graphs are ignored.
+func (n *Node) DependsOn(deps ...*Node) error {
+	n.dag.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer n.dag.mx.Unlock() // Release the lock after the function finishes.
+
+	for _, dep := range deps {
+		if dep.dag == n.dag && dep.reaches(n) {
+			return ErrDependencyCycle
+		}
+	}
+
+	for _, dep := range deps {
+		if dep.dag == n.dag {
+			n.deps = append(n.deps, dep)
+		}
+	}
+
+	return nil
+}
+
+// reaches returns true if the node is the target or depends on it, directly or not.
+// The caller must hold the mutex of the graph.
+func (n *Node) reaches(target *Node) bool {
+	if n == target {
+		return true
+	}
+
+	for _, dep := range n.deps {
+		if dep.reaches(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CloseContext closes each resource once all the resources depending on it are closed,
+// the independent ones concurrently. Once the context is done, the closers not started yet
+// are skipped and the context error is returned along with the errors of the closers.
+func (d *Dag) CloseContext(ctx context.Context) error {
+	d.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer d.mx.Unlock() // Release the lock after the function finishes.
+
+	var (
+		errs error      // This will store the accumulated errors.
+		mx   sync.Mutex // Local mutex for the errors, the closers run concurrently.
+	)
+
+	// Channels to signal when each closer finishes, awaited by its dependencies.
+	dones := make(map[*Node]chan struct{}, len(d.nodes))
+	dependents := make(map[*Node][]*Node, len(d.nodes))
+
+	for _, n := range d.nodes {
+		dones[n] = make(chan struct{})
+
+		for _, dep := range n.deps {
+			dependents[dep] = append(dependents[dep], n)
+		}
+	}
+
+	// The closers not started yet are skipped if a closer panics under the PanicAbort policy.
+	runCtx, abort := context.WithCancel(ctx)
+	defer abort()
+
+	wg := sync.WaitGroup{} // WaitGroup to wait for all closers to finish.
+	wg.Add(len(d.nodes))
+	d.start(len(d.nodes))
+
+	for _, node := range d.nodes {
+		go func(n *Node) {
+			defer wg.Done() // Signal that this goroutine is finished.
+
+			// Wait for the closers depending on this one. A closer aborting the sequence cancels runCtx
+			// before it is done, so runCtx is checked again once they are done.
+			for _, dependent := range dependents[n] {
+				select {
+				case <-runCtx.Done():
+					return // The context is done, the closer is not started.
+				case <-dones[dependent]:
+				}
+			}
+
+			if runCtx.Err() != nil {
+				return // The context is done, the closer is not started.
+			}
+
+			done := dones[n]
+
+			// Inner goroutine to call the Close method of the resource.
+			go func() {
+				var err error
+
+				aborted := callClose(ctx, n.closer, &err)
+
+				mx.Lock()
+				errs = multierr.Append(errs, err)
+				mx.Unlock()
+
+				if aborted {
+					abort() // Skip the closers not started yet.
+				}
+
+				d.done()
+				close(done) // Signal that the closer is done.
+			}()
+
+			select {
+			case <-runCtx.Done(): // If the context is cancelled or times out.
+			case <-done: // Wait until the closer finishes.
+			}
+		}(node)
+	}
+
+	wg.Wait() // Wait until all closers are finished or the context is done.
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if err := ctx.Err(); err != nil && d.Pending() > 0 {
+		return multierr.Append(errs, err) // Some closers are skipped or still running.
+	}
+
+	return errs
+}
+
+// Close attempts to close all resources in dependency order without context support,
+// within the timeout set by NewDag, if any.
+func (d *Dag) Close() error {
+	return closeWithin(d, d.timeout)
+}
+
+// WithContext embeds the Dag instance into the given context.
+func (d *Dag) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, d)
+}
@@ -0,0 +1,119 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDag(t *testing.T) {
+	var (
+		mx    sync.Mutex
+		order []string
+	)
+
+	record := func(name string) Closer {
+		return Fn(func() error {
+			mx.Lock()
+			defer mx.Unlock()
+
+			order = append(order, name)
+
+			return nil
+		})
+	}
+
+	d := &Dag{}
+	db := d.Add(record("db"))
+	cache := d.Add(record("cache"))
+	http := d.Add(record("http"), db, cache)
+	d.Add(record("grpc"), db)
+	d.Append(record("metrics"))
+
+	tracer := d.Add(record("tracer"))
+	require.NoError(t, tracer.DependsOn(http))
+
+	require.NoError(t, d.Close())
+	require.Len(t, order, 6)
+
+	index := make(map[string]int, len(order))
+	for i, name := range order {
+		index[name] = i
+	}
+
+	assert.Less(t, index["tracer"], index["http"])
+	assert.Less(t, index["http"], index["db"])
+	assert.Less(t, index["http"], index["cache"])
+	assert.Less(t, index["grpc"], index["db"])
+	assert.Equal(t, 6, d.Completed())
+}
+
+func TestDag_Cycle(t *testing.T) {
+	d := &Dag{}
+	a := d.Add(&mockCloser{})
+	b := d.Add(&mockCloser{}, a)
+	c := d.Add(&mockCloser{}, b)
+
+	assert.ErrorIs(t, a.DependsOn(c), ErrDependencyCycle)
+	assert.ErrorIs(t, a.DependsOn(a), ErrDependencyCycle)
+	assert.NoError(t, c.DependsOn(a))
+
+	assert.Equal(t, []Step{
+		{Name: "*shutdown.mockCloser", Phase: 0, Unnamed: true},
+		{Name: "*shutdown.mockCloser", Phase: 1, Unnamed: true},
+		{Name: "*shutdown.mockCloser", Phase: 2, Unnamed: true},
+	}, d.Plan())
+}
+
+func TestDag_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	closed := false
+
+	d, err := NewDag(WithCloseTimeout(10 * time.Millisecond))
+	require.NoError(t, err)
+
+	db := d.Add(Fn(func() error {
+		closed = true
+		return nil
+	}))
+	d.Add(Fn(func() error {
+		<-release
+		return nil
+	}), db)
+
+	assert.ErrorIs(t, d.Close(), context.DeadlineExceeded)
+	assert.False(t, closed, "Expected the dependency not to be closed before its dependent")
+}
+
+func TestDag_Errors(t *testing.T) {
+	errDB, errHTTP := errors.New("db"), errors.New("http")
+
+	d := &Dag{}
+	db := d.Add(Fn(func() error { return errDB }))
+	d.Add(Fn(func() error { return errHTTP }), db)
+
+	err := d.Close()
+	assert.ErrorIs(t, err, errDB)
+	assert.ErrorIs(t, err, errHTTP)
+}
+
+func TestDag_PanicAbort(t *testing.T) {
+	SetPanicPolicy(PanicAbort)
+	defer SetPanicPolicy(PanicContinue)
+
+	for i := 0; i < 20; i++ { // The dependency is done and the abort is signaled at once
+		d := &Dag{}
+		db := &flagCloser{}
+		d.Add(panicking(), d.Add(db))
+
+		assert.ErrorAs(t, d.Close(), new(*PanicError))
+		assert.False(t, db.closed)
+	}
+}
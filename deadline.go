@@ -0,0 +1,51 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"flag"
+)
+
+// ErrNoDeadline is returned by the package-level CloseContext when RequireDeadline is set
+// and the context has no deadline.
+var ErrNoDeadline = errors.New("shutdown: close context has no deadline")
+
+var (
+	pkgRequireDeadline bool             // Whether the package-level CloseContext requires a deadline
+	inTest             = flagRegistered // Reports whether the program is a test binary
+)
+
+// flagRegistered reports whether the testing flags are registered, i.e. the program is a test binary.
+func flagRegistered() bool {
+	return flag.Lookup("test.v") != nil
+}
+
+// RequireDeadline makes the package-level CloseContext return ErrNoDeadline, without closing anything,
+// when called with a context lacking a deadline, since an unbounded graceful shutdown in production
+// is almost always a misconfiguration. Close honors it unless a default timeout is set with WithCloseTimeout.
+// The requirement is not enforced in test binaries.
+func RequireDeadline() Option {
+	return func(o *options) error {
+		if err := o.applies("RequireDeadline", targetInit); err != nil {
+			return err
+		}
+
+		o.requireDeadline = true
+
+		return nil
+	}
+}
+
+// checkDeadline returns ErrNoDeadline if a deadline is required but ctx has none.
+// The lock must be held.
+func checkDeadline(ctx context.Context) error {
+	if !pkgRequireDeadline || inTest() {
+		return nil
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		return ErrNoDeadline
+	}
+
+	return nil
+}
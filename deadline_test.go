@@ -0,0 +1,45 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireDeadline(t *testing.T) {
+	resetPackage(&Lifo{})
+	require.NoError(t, Init(RequireDeadline()))
+
+	inTest = func() bool { return false }
+
+	defer func() {
+		inTest = flagRegistered
+		pkgRequireDeadline = false
+	}()
+
+	closed := false
+	Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	assert.ErrorIs(t, Close(), ErrNoDeadline)
+	assert.ErrorIs(t, CloseContext(context.Background()), ErrNoDeadline)
+	assert.False(t, closed)
+
+	assert.NoError(t, CloseWithTimeout(time.Minute))
+	assert.True(t, closed)
+}
+
+func TestRequireDeadline_InTest(t *testing.T) {
+	resetPackage(&Lifo{})
+	require.NoError(t, Init(RequireDeadline()))
+
+	defer func() { pkgRequireDeadline = false }()
+
+	assert.True(t, inTest())
+	assert.NoError(t, CloseContext(context.Background()))
+}
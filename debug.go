@@ -0,0 +1,49 @@
+package shutdown
+
+import (
+	"errors"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// debugReadHeaderTimeout limits the time to read the request headers of the debug server.
+const debugReadHeaderTimeout = 10 * time.Second
+
+// StartDebugServer starts an HTTP server serving net/http/pprof (/debug/pprof/) and expvar (/debug/vars)
+// on a separate address, and registers it with AppendFinal, so the endpoints stay alive as long as
+// possible during the shutdown to debug hangs.
+//
+// Parameters:
+// - addr: The address to listen on, e.g. "localhost:6060" or "localhost:0".
+//
+// Returns:
+// - The address the server listens on, or an error if the listener cannot be created.
+func StartDebugServer(addr string) (net.Addr, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: debugReadHeaderTimeout}
+
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_ = l.Close()
+		}
+	}()
+
+	appendFinal(srv)
+
+	return l.Addr(), nil
+}
@@ -0,0 +1,29 @@
+package shutdown
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartDebugServer(t *testing.T) {
+	resetPackage(&Lifo{})
+	pkgFinal = &Lifo{}
+
+	addr, err := StartDebugServer("127.0.0.1:0")
+	require.NoError(t, err)
+
+	for _, path := range []string{"/debug/pprof/", "/debug/vars"} {
+		resp, err := http.Get("http://" + addr.String() + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	require.NoError(t, Close())
+
+	_, err = http.Get("http://" + addr.String() + "/debug/vars")
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package shutdown
+
+// decorator is embedded by the closures wrapping another Closure, e.g. WithDrainDelay, in place of the
+// wrapped Closure. Besides the Closure methods, it forwards the optional capabilities of the wrapped
+// closure, so that a decorated closure is still planned, capped and migrated as the wrapped one is.
+type decorator struct {
+	Closure // The wrapped closure
+}
+
+// decorated returns the wrapped closure.
+func (d decorator) decorated() Closure {
+	return d.Closure
+}
+
+// undecorate returns the closure wrapped by the decorators, to check its capabilities.
+func undecorate(c Closure) Closure {
+	for {
+		d, ok := c.(interface{ decorated() Closure })
+		if !ok {
+			return c
+		}
+
+		c = d.decorated()
+	}
+}
+
+// Plan returns the plan of the wrapped closure, nil if it cannot plan.
+func (d decorator) Plan() []Step {
+	if p, ok := d.Closure.(Planner); ok {
+		return p.Plan()
+	}
+
+	return nil
+}
+
+// Pending returns the number of closers of the wrapped closure that are not closed yet,
+// zero if it does not count them.
+func (d decorator) Pending() int {
+	if p, ok := d.Closure.(pendingCounter); ok {
+		return p.Pending()
+	}
+
+	return 0
+}
+
+// drain removes and returns the closers of the wrapped closure, nil if it cannot hand them over.
+func (d decorator) drain() []Closer {
+	if dr, ok := d.Closure.(drainer); ok {
+		return dr.drain()
+	}
+
+	return nil
+}
+
+// list returns the closers of the wrapped closure, nil if it cannot list them.
+func (d decorator) list() []Closer {
+	if l, ok := d.Closure.(lister); ok {
+		return l.list()
+	}
+
+	return nil
+}
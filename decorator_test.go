@@ -0,0 +1,41 @@
+package shutdown
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorator(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, decorate := range map[string]func(Closure) Closure{
+		"history":      func(c Closure) Closure { return WithHistory(c, FileStore(filepath.Join(dir, "history"))) },
+		"slo":          func(c Closure) Closure { return WithSLO(c, SLO{}, nil, nil) },
+		"profile":      func(c Closure) Closure { return WithProfile(c, dir) },
+		"jitter":       func(c Closure) Closure { return WithStartJitter(c, time.Millisecond) },
+		"cancellation": IgnoreCancellation,
+		"drain delay":  func(c Closure) Closure { return WithDrainDelay(c, time.Millisecond) },
+		"trace":        WithTrace,
+	} {
+		closure := decorate(&Lifo{})
+		closure.Append(Named("db", &mockCloser{}))
+
+		assert.Equal(t, 1, closure.(pendingCounter).Pending(), name)
+		assert.Len(t, closure.(lister).list(), 1, name)
+
+		if steps := closure.(Planner).Plan(); assert.Len(t, steps, 1, name) {
+			assert.Equal(t, "db", steps[0].Name, name)
+		}
+
+		resetPackage(closure)
+		SetPackageClosure(&Fifo{})
+		assert.Equal(t, 1, pkgClosure.(pendingCounter).Pending(), "Expected the closers of %s to be migrated", name)
+		assert.Empty(t, closure.(lister).list(), name)
+	}
+
+	assert.Equal(t, &Lifo{}, undecorate(WithTrace(WithStartJitter(&Lifo{}, time.Second))))
+	assert.Nil(t, WithTrace(plainClosure{&Lifo{}}).(Planner).Plan())
+}
@@ -0,0 +1,109 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// DirtyMarker persists the fact that a shutdown is in progress.
+// It is marked when the shutdown starts and cleared only on clean completion,
+// so the next startup can detect an unclean previous shutdown.
+type DirtyMarker interface {
+	Mark() error  // Marks the shutdown as in progress
+	Clear() error // Clears the mark after a clean shutdown
+}
+
+// FileMarker is a DirtyMarker backed by a marker file at the given path.
+type FileMarker string
+
+// Mark writes the marker file, containing the process ID.
+func (f FileMarker) Mark() error {
+	if err := writeFileAtomic(string(f), []byte(strconv.Itoa(os.Getpid())+"\n")); err != nil {
+		return fmt.Errorf("shutdown: cannot write dirty marker: %w", err)
+	}
+
+	return nil
+}
+
+// Clear removes the marker file.
+func (f FileMarker) Clear() error {
+	if err := os.Remove(string(f)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("shutdown: cannot remove dirty marker: %w", err)
+	}
+
+	return nil
+}
+
+// Dirty reports whether the marker file exists, i.e. the previous shutdown was not clean.
+// Call it at startup to trigger recovery logic (cache rebuild, WAL replay), then Clear it.
+func (f FileMarker) Dirty() (bool, error) {
+	_, err := os.Stat(string(f))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// MarkerFuncs is an adapter to allow the use of user hooks as a DirtyMarker.
+type MarkerFuncs struct {
+	OnMark  func() error // Called when the shutdown starts
+	OnClear func() error // Called after a clean shutdown
+}
+
+// Mark calls OnMark, if set.
+func (m MarkerFuncs) Mark() error {
+	if m.OnMark == nil {
+		return nil
+	}
+
+	return m.OnMark()
+}
+
+// Clear calls OnClear, if set.
+func (m MarkerFuncs) Clear() error {
+	if m.OnClear == nil {
+		return nil
+	}
+
+	return m.OnClear()
+}
+
+// dirtyClosure marks the close sequence of the wrapped Closure as dirty until it completes cleanly.
+type dirtyClosure struct {
+	decorator             // The wrapped closure
+	marker    DirtyMarker // The marker of the shutdown in progress
+}
+
+// WithDirtyMarker wraps the closure so that the marker is marked when its close sequence starts
+// and cleared only if the close sequence completes without errors.
+func WithDirtyMarker(closure Closure, marker DirtyMarker) Closure {
+	return &dirtyClosure{decorator: decorator{closure}, marker: marker}
+}
+
+// CloseContext marks the shutdown, closes the wrapped closure, and clears the mark on success.
+func (d *dirtyClosure) CloseContext(ctx context.Context) error {
+	errs := d.marker.Mark()
+	errs = multierr.Append(errs, d.Closure.CloseContext(ctx))
+
+	if errs != nil {
+		return errs // Keep the mark, the shutdown was not clean
+	}
+
+	return d.marker.Clear()
+}
+
+// Close closes the wrapped closure without context support, keeping the mark on failure.
+func (d *dirtyClosure) Close() error {
+	return d.CloseContext(context.Background())
+}
+
+// WithContext associates the closure with the given context.
+func (d *dirtyClosure) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, d)
+}
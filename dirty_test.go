@@ -0,0 +1,57 @@
+package shutdown
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDirtyMarker(t *testing.T) {
+	marker := FileMarker(filepath.Join(t.TempDir(), "dirty"))
+
+	dirtyDuringClose := false
+	closure := WithDirtyMarker(&Lifo{}, marker)
+	closure.Append(Fn(func() error {
+		dirtyDuringClose, _ = marker.Dirty()
+		return nil
+	}))
+
+	require.NoError(t, closure.Close())
+	assert.True(t, dirtyDuringClose)
+
+	dirty, err := marker.Dirty()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+}
+
+func TestWithDirtyMarker_Unclean(t *testing.T) {
+	marker := FileMarker(filepath.Join(t.TempDir(), "dirty"))
+
+	closure := WithDirtyMarker(&Lifo{}, marker)
+	closure.Append(Fn(func() error { return errors.New("close error") }))
+
+	require.Error(t, closure.Close())
+
+	dirty, err := marker.Dirty()
+	require.NoError(t, err)
+	assert.True(t, dirty)
+
+	require.NoError(t, marker.Clear())
+	require.NoError(t, marker.Clear())
+}
+
+func TestMarkerFuncs(t *testing.T) {
+	var events []string
+
+	marker := MarkerFuncs{
+		OnMark:  func() error { events = append(events, "mark"); return nil },
+		OnClear: func() error { events = append(events, "clear"); return nil },
+	}
+
+	assert.NoError(t, WithDirtyMarker(&Fifo{}, marker).Close())
+	assert.Equal(t, []string{"mark", "clear"}, events)
+	assert.NoError(t, MarkerFuncs{}.Mark())
+}
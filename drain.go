@@ -0,0 +1,81 @@
+package shutdown
+
+// drainer is implemented by the closures able to hand over their registered closers.
+type drainer interface {
+	drain() []Closer // Removes and returns the registered closers, in the order of appending
+}
+
+// drain removes and returns the closers of the Lifo stack, in the order of appending.
+func (l *Lifo) drain() []Closer {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	closers := l.stack
+	l.stack = nil
+	l.drained(len(closers))
+
+	return closers
+}
+
+// drain removes and returns the closers of the Fifo queue, in the order of appending.
+func (f *Fifo) drain() []Closer {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	closers := f.queue
+	f.queue = nil
+	f.drained(len(closers))
+
+	return closers
+}
+
+// drain removes and returns the closers of the Group, in the order of appending.
+// The ordering constraints of AppendAfter are not preserved.
+func (g *Group) drain() []Closer {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	closers := make([]Closer, 0, len(g.closers))
+	for _, h := range g.closers {
+		closers = append(closers, h.closer)
+	}
+
+	g.closers = nil
+	g.drained(len(closers))
+
+	return closers
+}
+
+// drain removes and returns the closers of the Priority, in the order of appending.
+// The priorities are not preserved.
+func (p *Priority) drain() []Closer {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	closers := make([]Closer, 0, len(p.closers))
+	for _, c := range p.closers {
+		closers = append(closers, c.closer)
+	}
+
+	p.closers = nil
+	p.drained(len(closers))
+
+	return closers
+}
+
+// drain removes and returns the closers of the Dag, in the order of appending.
+// The dependencies are not preserved.
+func (d *Dag) drain() []Closer {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	closers := make([]Closer, 0, len(d.nodes))
+	for _, n := range d.nodes {
+		closers = append(closers, n.closer)
+	}
+
+	d.nodes = nil
+	d.drained(len(closers))
+
+	return closers
+}
@@ -0,0 +1,148 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// DefaultBackoff is the delay of a Buffer between the flushes that made no progress.
+const DefaultBackoff = 10 * time.Millisecond
+
+// DefaultMaxStalls is the number of consecutive flushes without progress after which a Buffer
+// drained without a deadline gives up.
+const DefaultMaxStalls = 100
+
+// ErrStalled is returned by Buffer.Drain when the flushes made no progress MaxStalls times in a row
+// and the context has no deadline to stop them.
+var ErrStalled = errors.New("drain: flushes make no progress")
+
+// Flusher is an in-memory outbound queue, e.g. a buffer of events waiting to be sent.
+type Flusher interface {
+	Len() int                        // Returns the number of the buffered items
+	Flush(ctx context.Context) error // Flushes some or all of the buffered items, e.g. one batch
+}
+
+// FlushStats is the accounting of the items of a Buffer.
+type FlushStats struct {
+	Flushed int // The number of items flushed so far
+	Pending int // The number of items still buffered
+	Dropped int // The number of items left when the draining has stopped
+}
+
+// Buffer is a drainer flushing a Flusher until it is empty or the context is done.
+// The flushes are repeated while they make progress, so a downstream applying back-pressure
+// is given the whole deadline; a flush that makes no progress is retried after the backoff.
+// Without a deadline, the draining gives up after MaxStalls flushes in a row made no progress.
+type Buffer struct {
+	Progress  func(FlushStats) // Called after each flush, optional
+	Backoff   time.Duration    // The delay after a flush that made no progress, DefaultBackoff if zero
+	MaxStalls int              // The flushes in a row without progress allowed without a deadline, DefaultMaxStalls if zero
+
+	name    string     // The name of the buffer, used in the errors
+	flusher Flusher    // The flushed queue
+	mx      sync.Mutex // Mutex for thread safety of the stats
+	stats   FlushStats // The accounting of the items
+}
+
+// NewBuffer returns a drainer flushing the queue.
+func NewBuffer(name string, flusher Flusher) *Buffer {
+	return &Buffer{name: name, flusher: flusher}
+}
+
+// Stats returns the accounting of the items flushed and dropped so far.
+func (b *Buffer) Stats() FlushStats {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	return b.stats
+}
+
+// Drain flushes the queue until it is empty or the context is done. If the context has no deadline,
+// the draining also stops once MaxStalls flushes in a row made no progress. If items are left,
+// the returned error reports their number along with the context error or ErrStalled,
+// and the error of the last flush.
+func (b *Buffer) Drain(ctx context.Context) error {
+	backoff := b.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	maxStalls := b.MaxStalls
+	if maxStalls <= 0 {
+		maxStalls = DefaultMaxStalls
+	}
+
+	_, bounded := ctx.Deadline()
+
+	var (
+		lastErr error
+		stalls  int // The flushes in a row without progress
+	)
+
+	for pending := b.flusher.Len(); pending > 0; {
+		if ctx.Err() != nil {
+			return b.drop(pending, multierr.Append(ctx.Err(), lastErr))
+		}
+
+		lastErr = b.flusher.Flush(ctx)
+		left := b.flusher.Len()
+		flushed := pending - left
+
+		if flushed < 0 {
+			flushed = 0 // New items have been buffered meanwhile
+		}
+
+		b.report(flushed, left)
+
+		if flushed > 0 {
+			stalls = 0
+		} else {
+			stalls++
+
+			if !bounded && stalls >= maxStalls {
+				return b.drop(left, multierr.Append(ErrStalled, lastErr))
+			}
+
+			timer := time.NewTimer(backoff)
+
+			select {
+			case <-ctx.Done():
+			case <-timer.C:
+			}
+
+			timer.Stop()
+		}
+
+		pending = left
+	}
+
+	return nil
+}
+
+// report accounts the flushed items and reports the progress.
+func (b *Buffer) report(flushed, pending int) {
+	b.mx.Lock()
+	b.stats.Flushed += flushed
+	b.stats.Pending = pending
+	stats := b.stats
+	b.mx.Unlock()
+
+	if b.Progress != nil {
+		b.Progress(stats)
+	}
+}
+
+// drop accounts the items left in the queue and returns the error of the draining.
+func (b *Buffer) drop(pending int, err error) error {
+	b.mx.Lock()
+	b.stats.Pending = pending
+	b.stats.Dropped = pending
+	b.mx.Unlock()
+
+	return fmt.Errorf("drain: buffer %s: %d items dropped: %w", b.name, pending, err)
+}
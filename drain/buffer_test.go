@@ -0,0 +1,92 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchFlusher flushes up to batch items per flush, failing while blocked.
+type batchFlusher struct {
+	mx      sync.Mutex
+	items   int
+	batch   int
+	blocked bool
+}
+
+func (f *batchFlusher) Len() int {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	return f.items
+}
+
+func (f *batchFlusher) Flush(context.Context) error {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	if f.blocked {
+		return errors.New("downstream unavailable")
+	}
+
+	n := f.batch
+	if n > f.items {
+		n = f.items
+	}
+
+	f.items -= n
+
+	return nil
+}
+
+func TestBuffer(t *testing.T) {
+	var progress []FlushStats
+
+	b := NewBuffer("events", &batchFlusher{items: 5, batch: 2})
+	b.Progress = func(s FlushStats) {
+		progress = append(progress, s)
+	}
+
+	require.NoError(t, b.Drain(context.Background()))
+	assert.Equal(t, FlushStats{Flushed: 5}, b.Stats())
+	assert.Equal(t, []FlushStats{
+		{Flushed: 2, Pending: 3},
+		{Flushed: 4, Pending: 1},
+		{Flushed: 5},
+	}, progress)
+}
+
+func TestBuffer_Dropped(t *testing.T) {
+	f := &batchFlusher{items: 3, batch: 2, blocked: true}
+
+	b := NewBuffer("events", f)
+	b.Backoff = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := b.Drain(ctx)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Contains(t, err.Error(), "drain: buffer events: 3 items dropped")
+	assert.Contains(t, err.Error(), "downstream unavailable")
+	assert.Equal(t, FlushStats{Pending: 3, Dropped: 3}, b.Stats())
+}
+
+func TestBuffer_Stalled(t *testing.T) {
+	b := NewBuffer("events", &batchFlusher{items: 3, batch: 2, blocked: true})
+	b.Backoff = time.Millisecond
+	b.MaxStalls = 3
+
+	err := b.Drain(context.Background()) // No deadline
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrStalled))
+	assert.Contains(t, err.Error(), "drain: buffer events: 3 items dropped")
+	assert.Contains(t, err.Error(), "downstream unavailable")
+	assert.Equal(t, FlushStats{Pending: 3, Dropped: 3}, b.Stats())
+}
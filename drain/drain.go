@@ -0,0 +1,103 @@
+// Package drain provides the building blocks for draining channel-based pipelines in order:
+// stop the producers, flush the queues, stop the consumers, all under one deadline.
+// A Pipeline is a closer, so the whole pipeline is registered as a single closer.
+package drain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// Drainer is a part of a pipeline that can be drained.
+type Drainer interface {
+	Drain(ctx context.Context) error // Drains the part, returns the context error if it is done first
+}
+
+// DrainerFunc is an adapter to allow the use of ordinary functions as drainers.
+type DrainerFunc func(ctx context.Context) error
+
+// Drain calls f(ctx).
+func (f DrainerFunc) Drain(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Stage is a stage of a pipeline, e.g. a pool of producers or consumers.
+type Stage struct {
+	Name string          // The name of the stage, used in the errors
+	Stop func()          // Stops the stage from taking new work, e.g. closes its input channel; optional
+	Done <-chan struct{} // Closed when the stage has finished its remaining work
+}
+
+// Drain stops the stage and waits until it has finished its remaining work or the context is done.
+func (s Stage) Drain(ctx context.Context) error {
+	if s.Stop != nil {
+		s.Stop()
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("drain: stage %s: %w", s.Name, ctx.Err())
+	case <-s.Done:
+		return nil
+	}
+}
+
+// Queue returns a drainer flushing the items left in the channel to the handler,
+// until the channel is closed by its producers or the context is done.
+// The errors of the handler are combined and do not stop the flushing.
+func Queue[T any](name string, ch <-chan T, handle func(T) error) Drainer {
+	return DrainerFunc(func(ctx context.Context) error {
+		var errs error
+
+		for {
+			select {
+			case <-ctx.Done():
+				return multierr.Append(errs, fmt.Errorf("drain: queue %s: %w", name, ctx.Err()))
+			case item, ok := <-ch:
+				if !ok {
+					return errs
+				}
+
+				errs = multierr.Append(errs, handle(item))
+			}
+		}
+	})
+}
+
+// Pipeline drains its drainers in order, e.g. the producers, then the queues, then the consumers.
+type Pipeline struct {
+	drainers []Drainer // The drainers in the order of draining
+}
+
+// New returns a pipeline draining the given drainers in order.
+func New(drainers ...Drainer) *Pipeline {
+	return &Pipeline{drainers: drainers}
+}
+
+// Append adds a drainer to be drained after the already added ones.
+func (p *Pipeline) Append(d Drainer) {
+	p.drainers = append(p.drainers, d)
+}
+
+// CloseContext drains the drainers in order under the deadline of ctx.
+// The errors are combined; once the context is done, the remaining drainers are skipped.
+func (p *Pipeline) CloseContext(ctx context.Context) error {
+	var errs error
+
+	for _, d := range p.drainers {
+		if err := ctx.Err(); err != nil {
+			return multierr.Append(errs, err)
+		}
+
+		errs = multierr.Append(errs, d.Drain(ctx))
+	}
+
+	return errs
+}
+
+// Close drains the drainers in order without a deadline.
+func (p *Pipeline) Close() error {
+	return p.CloseContext(context.Background())
+}
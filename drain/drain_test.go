@@ -0,0 +1,101 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
+)
+
+func TestPipeline(t *testing.T) {
+	var (
+		produced = make(chan int, 10)
+		stopped  = make(chan struct{})
+		done     = make(chan struct{})
+		mx       sync.Mutex
+		handled  []int
+	)
+
+	// Producer: stops on Stop and closes the queue.
+	go func() {
+		defer close(done)
+		defer close(produced)
+
+		for i := 0; ; i++ {
+			select {
+			case <-stopped:
+				return
+			case produced <- i:
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	p := New(
+		Stage{Name: "producer", Stop: func() { once.Do(func() { close(stopped) }) }, Done: done},
+		Queue("items", produced, func(i int) error {
+			mx.Lock()
+			handled = append(handled, i)
+			mx.Unlock()
+
+			return nil
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, p.CloseContext(ctx))
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	for i, item := range handled {
+		assert.Equal(t, i, item)
+	}
+}
+
+func TestPipeline_Deadline(t *testing.T) {
+	var order []string
+
+	p := New()
+	p.Append(Stage{Name: "stuck", Done: make(chan struct{})})
+	p.Append(DrainerFunc(func(context.Context) error {
+		order = append(order, "skipped")
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := p.CloseContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "drain: stage stuck")
+	assert.Empty(t, order)
+}
+
+func TestQueue_Errors(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+
+	errOdd := errors.New("odd")
+
+	err := New(Queue("numbers", ch, func(i int) error {
+		if i%2 == 1 {
+			return errOdd
+		}
+
+		return nil
+	})).Close()
+
+	assert.ErrorIs(t, err, errOdd)
+	assert.Len(t, multierr.Errors(err), 2)
+}
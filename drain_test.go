@@ -0,0 +1,52 @@
+package shutdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPackageClosure_Migrates(t *testing.T) {
+	old := &Group{}
+	resetPackage(old)
+
+	var order []string
+
+	closer := func(name string) Closer {
+		return Fn(func() error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	Append(closer("first"))
+	Append(closer("second"))
+
+	fifo := &Fifo{}
+	prev := SetPackageClosure(fifo)
+	assert.Equal(t, old, prev)
+	assert.Zero(t, old.Pending())
+	assert.Equal(t, 2, fifo.Pending())
+
+	Append(closer("third"))
+	assert.NoError(t, Close())
+	assert.Equal(t, []string{"first", "second", "third"}, order)
+}
+
+func TestDrainClosers(t *testing.T) {
+	for name, closure := range map[string]interface {
+		Closure
+		drainer
+	}{
+		"lifo":  &Lifo{},
+		"fifo":  &Fifo{},
+		"group": &Group{},
+	} {
+		first, second := &mockCloser{}, &mockCloser{}
+		closure.Append(first)
+		closure.Append(second)
+
+		assert.Equal(t, []Closer{first, second}, closure.drain(), name)
+		assert.Empty(t, closure.drain(), name)
+	}
+}
@@ -0,0 +1,105 @@
+package shutdown
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"time"
+)
+
+// ForceExitCode is the default exit code of the forced shutdown, see WithForceExitCode.
+const ForceExitCode = 1
+
+var (
+	started       atomic.Bool  // Whether the package-level close sequence has started
+	osExit        = os.Exit    // Exits the process on escalation
+	forceExitCode atomic.Int32 // The exit code of the forced shutdown, ForceExitCode if zero
+)
+
+// WithForceExitCode sets the exit code of the forced shutdown of WaitForSignalsWithEscalation
+// and CloseOnSignalForce, e.g. 130 as a shell does for Ctrl-C.
+func WithForceExitCode(code int) Option {
+	return func(o *options) error {
+		if err := o.applies("WithForceExitCode", targetInit); err != nil {
+			return err
+		}
+
+		if code <= 0 || code > 255 {
+			return fmt.Errorf("%w: force exit code %d out of range 1..255", ErrInvalidOption, code)
+		}
+
+		o.forceExitCode = code
+
+		return nil
+	}
+}
+
+// exitCode returns the exit code of the forced shutdown.
+func exitCode() int {
+	if code := forceExitCode.Load(); code != 0 {
+		return int(code)
+	}
+
+	return ForceExitCode
+}
+
+// WaitForSignalsWithEscalation is similar to WaitForSignals, but once a signal is received it also
+// starts a timer: if the graceful shutdown (the package-level Close or CloseContext) has not begun
+// within escalateAfter, e.g. because the main goroutine is stuck, the process exits immediately
+// with the force exit code, independently of a second signal arriving.
+func WaitForSignalsWithEscalation(logger Logger, escalateAfter time.Duration, sig ...os.Signal) {
+	WaitForSignals(logger, sig...)
+
+	time.AfterFunc(escalateAfter, func() {
+		if started.Load() {
+			return
+		}
+
+		logger.Msgf("Shutdown has not begun %s after the signal, forcing exit", escalateAfter)
+		osExit(exitCode())
+	})
+}
+
+// CloseOnSignalForce waits for the specified signals and then closes the global closure, like CloseOnSignal,
+// but while the closing is in progress, the forceAfter-th signal (counting the first one) exits the process
+// immediately with the force exit code, the usual "press Ctrl-C again to force quit" behavior.
+// A forceAfter lower than 2 means the second signal.
+func CloseOnSignalForce(logger Logger, forceAfter int, sig ...os.Signal) error {
+	if forceAfter < 2 {
+		forceAfter = 2
+	}
+
+	c := make(chan os.Signal, 1) // Channel to listen for signals.
+	notifySignals(c, sig)
+	defer signal.Stop(c)
+
+	s := <-c
+	handleQuit(s)
+	logger.Msgf("Received signal: %s", s)
+	useLogger(logger)
+
+	done := make(chan struct{}) // Closed once the closing is finished.
+	defer close(done)
+
+	go func() {
+		for n := 2; ; n++ {
+			select {
+			case <-done:
+				return
+			case s := <-c:
+				if n < forceAfter {
+					logger.Msgf("Received signal: %s, shutting down, %d more to force exit", s, forceAfter-n)
+					continue
+				}
+
+				logger.Msgf("Received signal: %s, forcing exit", s)
+				osExit(exitCode())
+
+				return
+			}
+		}
+	}()
+
+	return Close()
+}
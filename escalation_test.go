@@ -0,0 +1,106 @@
+package shutdown
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForSignalsWithEscalation(t *testing.T) {
+	exited := make(chan int, 1)
+	osExit = func(code int) { exited <- code }
+
+	defer func() { osExit = os.Exit }()
+
+	resetPackage(&Lifo{})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	}()
+
+	logger := &mockLogger{}
+	WaitForSignalsWithEscalation(logger, 20*time.Millisecond, syscall.SIGUSR2)
+
+	select {
+	case code := <-exited:
+		assert.Equal(t, ForceExitCode, code)
+	case <-time.After(time.Second):
+		t.Fatal("the shutdown was not escalated")
+	}
+}
+
+func TestWaitForSignalsWithEscalation_Started(t *testing.T) {
+	exited := make(chan int, 1)
+	osExit = func(code int) { exited <- code }
+
+	defer func() { osExit = os.Exit }()
+
+	resetPackage(&Lifo{})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	}()
+
+	WaitForSignalsWithEscalation(&mockLogger{}, 20*time.Millisecond, syscall.SIGUSR2)
+	assert.NoError(t, Close())
+
+	select {
+	case <-exited:
+		t.Fatal("the shutdown was escalated although it has begun")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCloseOnSignalForce(t *testing.T) {
+	require.NoError(t, Init(WithForceExitCode(130)))
+	defer func() { forceExitCode.Store(0) }()
+
+	exited := make(chan int, 1)
+	release := make(chan struct{})
+	osExit = func(code int) {
+		exited <- code
+		close(release) // Let the stuck closer return, as the process would be gone
+	}
+
+	defer func() { osExit = os.Exit }()
+
+	closing := make(chan struct{})
+
+	resetPackage(&Lifo{})
+	Append(Fn(func() error {
+		close(closing)
+		<-release
+		return nil
+	}))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+
+		<-closing
+
+		for i := 0; i < 2; i++ {
+			_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	logger := &mockLogger{}
+	assert.NoError(t, CloseOnSignalForce(logger, 3, syscall.SIGUSR2))
+
+	select {
+	case code := <-exited:
+		assert.Equal(t, 130, code)
+	default:
+		t.Fatal("Expected the third signal to force the exit")
+	}
+
+	assert.Contains(t, logger.messages, "Received signal: user defined signal 2, shutting down, 1 more to force exit")
+	assert.Equal(t, "Received signal: user defined signal 2, forcing exit", getLastLoggedMessage(logger))
+}
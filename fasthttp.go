@@ -0,0 +1,67 @@
+package shutdown
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// FastHTTPServer is the part of *fasthttp.Server used by FastHTTP,
+// so that this package does not depend on fasthttp.
+type FastHTTPServer interface {
+	ShutdownWithContext(ctx context.Context) error
+}
+
+// FastHTTPRequestCtx is the part of *fasthttp.RequestCtx used by FastHTTPHandler.
+type FastHTTPRequestCtx interface {
+	SetConnectionClose()
+}
+
+// FastHTTPCloser gracefully closes a fasthttp server.
+// The handlers are expected to be wrapped with FastHTTPHandler, so that the responses
+// sent during the shutdown close their connection and the closer waits for the handlers
+// the server does not track, e.g. the hijacked ones.
+type FastHTTPCloser struct {
+	srv      FastHTTPServer // The server to close
+	inFlight InFlight       // Tracks the handlers in progress
+	closing  atomic.Bool    // Whether the shutdown has started
+}
+
+// FastHTTP returns a closer for the given fasthttp server.
+func FastHTTP(srv FastHTTPServer) *FastHTTPCloser {
+	return &FastHTTPCloser{srv: srv}
+}
+
+// FastHTTPHandler wraps the fasthttp request handler, so that the closer waits for it,
+// and its connection is not kept alive once the shutdown has started:
+//
+//	closer := shutdown.FastHTTP(srv)
+//	srv.Handler = shutdown.FastHTTPHandler(closer, handler)
+func FastHTTPHandler[C FastHTTPRequestCtx](f *FastHTTPCloser, next func(C)) func(C) {
+	return func(ctx C) {
+		f.inFlight.Add()
+		defer f.inFlight.Done()
+
+		if f.closing.Load() {
+			ctx.SetConnectionClose() // Disable keep-alive, the client reconnects to another instance
+		}
+
+		next(ctx)
+	}
+}
+
+// CloseContext disables keep-alive for the following responses, shuts the server down
+// and waits for the wrapped handlers until the context is done.
+func (f *FastHTTPCloser) CloseContext(ctx context.Context) error {
+	f.closing.Store(true)
+
+	err := f.srv.ShutdownWithContext(ctx)
+
+	return multierr.Append(err, f.inFlight.Wait(ctx))
+}
+
+// Close shuts the server down without a deadline.
+func (f *FastHTTPCloser) Close() error {
+	return f.CloseContext(context.Background())
+}
@@ -0,0 +1,80 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeFastHTTPServer stands in for *fasthttp.Server.
+type fakeFastHTTPServer struct {
+	shutdown bool
+}
+
+func (s *fakeFastHTTPServer) ShutdownWithContext(context.Context) error {
+	s.shutdown = true
+	return nil
+}
+
+// fakeRequestCtx stands in for *fasthttp.RequestCtx.
+type fakeRequestCtx struct {
+	connectionClose bool
+}
+
+func (c *fakeRequestCtx) SetConnectionClose() {
+	c.connectionClose = true
+}
+
+func TestFastHTTP(t *testing.T) {
+	srv := &fakeFastHTTPServer{}
+	closer := FastHTTP(srv)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := FastHTTPHandler(closer, func(*fakeRequestCtx) {
+		close(started)
+		<-release
+	})
+
+	before := &fakeRequestCtx{}
+	go handler(before)
+	<-started
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- closer.Close()
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("closed before the handler finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-closed)
+	assert.True(t, srv.shutdown)
+	assert.False(t, before.connectionClose)
+
+	after := &fakeRequestCtx{}
+	FastHTTPHandler(closer, func(*fakeRequestCtx) {})(after)
+	assert.True(t, after.connectionClose)
+}
+
+func TestFastHTTP_Deadline(t *testing.T) {
+	closer := FastHTTP(&fakeFastHTTPServer{})
+	release := make(chan struct{})
+	defer close(release)
+
+	closer.inFlight.Add()
+	go func() {
+		<-release
+		closer.inFlight.Done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, closer.CloseContext(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,57 @@
+package shutdown
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// openFiles returns the open file descriptors of the process with their targets.
+func openFiles() (map[int]string, error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[int]string, len(entries))
+
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// The descriptor of the directory listing itself is already closed, skip it.
+		target, err := os.Readlink(filepath.Join("/proc/self/fd", entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		files[fd] = target
+	}
+
+	return files, nil
+}
+
+// residentMemory returns the resident set size of the process in bytes.
+func residentMemory() (uint64, error) {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, err
+	}
+
+	// The fields are the sizes in pages: total, resident, shared, text, lib, data, dirty.
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, errors.New("shutdown: unexpected format of /proc/self/statm")
+	}
+
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return pages * uint64(os.Getpagesize()), nil
+}
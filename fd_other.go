@@ -0,0 +1,18 @@
+//go:build !linux
+
+package shutdown
+
+import (
+	"errors"
+	"runtime"
+)
+
+// openFiles is not supported on this platform.
+func openFiles() (map[int]string, error) {
+	return nil, errors.New("shutdown: listing open files is not supported on " + runtime.GOOS)
+}
+
+// residentMemory is not supported on this platform.
+func residentMemory() (uint64, error) {
+	return 0, errors.New("shutdown: reading the resident memory is not supported on " + runtime.GOOS)
+}
@@ -2,15 +2,33 @@ package shutdown
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"time"
 
-	"go.uber.org/multierr"
+	"github.com/partyzanex/shutdown/internal/multierr"
 )
 
 // Fifo is a struct that manages a queue of resources that need to be closed, in First-In-First-Out order.
 type Fifo struct {
-	queue []Closer   // The list of resources to close
-	mx    sync.Mutex // Mutex for thread safety
+	progress
+
+	queue   []Closer      // The list of resources to close
+	mx      sync.Mutex    // Mutex for thread safety
+	timeout time.Duration // Timeout of Close, zero means no timeout
+	tracer  Tracer        // Tracer of the close sequence, nil if not traced
+}
+
+// NewFifo returns a Fifo configured by the options: WithCloseTimeout sets the timeout of Close,
+// and WithTracer traces the close sequence.
+// The zero Fifo is ready to use without options.
+func NewFifo(opts ...Option) (*Fifo, error) {
+	o, err := newOptions(targetFifo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fifo{timeout: o.timeout, tracer: o.tracer}, nil
 }
 
 // Append adds a new closer to the end of the Fifo queue.
@@ -18,19 +36,28 @@ func (f *Fifo) Append(closer Closer) {
 	f.mx.Lock()         // Acquiring the lock
 	defer f.mx.Unlock() // Making sure to release the lock after the function exits
 	f.queue = append(f.queue, closer)
+	f.added()
 }
 
 // CloseContext attempts to close each resource in the Fifo queue with context support.
-func (f *Fifo) CloseContext(ctx context.Context) error {
+func (f *Fifo) CloseContext(ctx context.Context) (err error) {
 	f.mx.Lock()         // Acquiring the lock
 	defer f.mx.Unlock() // Making sure to release the lock after the function exits
 
+	ctx, span := startSpan(ctx, f.tracer, SpanShutdown)
+	defer func() { span.End(err) }()
+
 	var errs error // This will store the accumulated errors
 
+	f.start(len(f.queue))
+
 	for _, closer := range f.queue {
 		next := make(chan struct{}) // Channel to signal completion of the closer
+		abort := false
+
 		go func() {
-			callClose(closer, &errs) // Call the close function and gather errors if any
+			abort = callTraced(ctx, f.tracer, closer, &errs) // Call the close function and gather errors if any
+			f.done()
 			close(next)
 		}()
 
@@ -40,15 +67,19 @@ func (f *Fifo) CloseContext(ctx context.Context) error {
 			return multierr.Append(errs, ctx.Err())
 		case <-next:
 			// Move to the next closer in the queue after the current one finishes
+			if abort {
+				return errs // A closer panicked under the PanicAbort policy
+			}
 		}
 	}
 
 	return errs // Return the accumulated errors
 }
 
-// Close attempts to close all resources in the Fifo queue without context support.
+// Close attempts to close all resources in the Fifo queue without context support,
+// within the timeout set by NewFifo, if any.
 func (f *Fifo) Close() error {
-	return f.CloseContext(context.Background()) // Using a background context which will never be cancelled
+	return closeWithin(f, f.timeout)
 }
 
 // WithContext associates the Fifo instance with the given context.
@@ -57,9 +88,23 @@ func (f *Fifo) WithContext(ctx context.Context) context.Context {
 	return ClosureToContext(ctx, f)
 }
 
-// callClose safely calls the Close method of the given closer and appends any errors.
-func callClose(closer Closer, errs *error) {
-	if err := closer.Close(); err != nil {
+// callClose safely closes the given closer with the context, if it is a ContextCloser, and appends any errors.
+// A panic is handled according to the global PanicPolicy.
+//
+// Returns:
+// - true if the remaining closers must not be started (PanicAbort policy).
+func callClose(ctx context.Context, closer Closer, errs *error) bool {
+	if isDeferred(closer) {
+		return false // The logger of the signal helpers is closed last, see WithLoggerMode
+	}
+
+	ctx, leave := enterCloser(ctx, closer) // Recognize the closer calling back into the package-level Close
+	defer leave()
+
+	err := closeRecorded(ctx, closer, PanicPolicy(panicPolicy.Load()))
+	if err != nil {
 		*errs = multierr.Append(*errs, err) // Accumulate the error if Close method fails
 	}
+
+	return errors.Is(err, errAbort)
 }
@@ -0,0 +1,92 @@
+package shutdown
+
+import (
+	"context"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// DefaultFinalReserve is the default time slice reserved for the final closers.
+const DefaultFinalReserve = time.Second
+
+var (
+	pkgFinal        = &Lifo{}             // Closers that always run last, in Last-In-First-Out order
+	pkgFinalReserve = DefaultFinalReserve // Time slice reserved for the final closers
+)
+
+// AppendFinal appends a closer that always runs after all other closers of the global closure,
+// even if they returned errors or the context is done. It is intended for flushing logs,
+// writing the shutdown report, and releasing PID files.
+//
+// If the context of the shutdown has a deadline, the other closers get the deadline
+// shortened by the reserve (see SetFinalReserve), which is left for the final closers.
+// The reserve never exceeds half of the remaining time, so the other closers always get their share.
+// If the context is already done when the final closers start, they get a fresh reserve.
+func AppendFinal(closer Closer) {
+	checkStrict(packageEntry(closer, callerPackage(1)))
+	appendFinal(closer)
+}
+
+// appendFinal appends the final closer without the strict check.
+func appendFinal(closer Closer) {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+	pkgFinal.Append(closer)
+}
+
+// SetFinalReserve sets the time slice reserved for the closers appended with AppendFinal.
+func SetFinalReserve(d time.Duration) {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+	pkgFinalReserve = d
+}
+
+// closeWithFinal closes the global closure, runs the verification checks and then closes
+// the final closers, keeping the reserved time slice for the latter. The caller must hold mu.
+func closeWithFinal(ctx context.Context) error {
+	mainCtx := ctx
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if reserve := finalReserve(deadline); reserve > 0 {
+			var cancel context.CancelFunc
+
+			mainCtx, cancel = context.WithDeadline(ctx, deadline.Add(-reserve))
+			defer cancel()
+		}
+	}
+
+	err := pkgClosure.CloseContext(mainCtx)
+
+	finalCtx := ctx
+
+	if ctx.Err() != nil && pkgFinalReserve > 0 {
+		var cancel context.CancelFunc
+
+		// The shutdown context is already done, give the final closers their reserve anyway.
+		finalCtx, cancel = context.WithTimeout(detachContext(ctx), pkgFinalReserve)
+		defer cancel()
+	}
+
+	err = multierr.Append(err, verify(finalCtx)) // Verify the shutdown before the final closers report it
+
+	return multierr.Append(err, pkgFinal.CloseContext(finalCtx))
+}
+
+// finalReserve returns the time slice to reserve for the final closers before the deadline:
+// none if no final closer is appended, and at most half of the remaining time. The caller must hold mu.
+func finalReserve(deadline time.Time) time.Duration {
+	pkgFinal.mx.Lock()
+	empty := len(pkgFinal.stack) == 0
+	pkgFinal.mx.Unlock()
+
+	if empty || pkgFinalReserve <= 0 {
+		return 0
+	}
+
+	if half := time.Until(deadline) / 2; half < pkgFinalReserve {
+		return half
+	}
+
+	return pkgFinalReserve
+}
@@ -0,0 +1,95 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendFinal(t *testing.T) {
+	resetPackage(&Fifo{})
+	pkgFinal = &Lifo{}
+	SetFinalReserve(50 * time.Millisecond)
+	defer SetFinalReserve(DefaultFinalReserve)
+
+	var order []string
+
+	AppendFinal(Fn(func() error {
+		order = append(order, "final")
+		return nil
+	}))
+	Append(Fn(func() error {
+		time.Sleep(time.Second) // Hangs beyond the deadline
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := CloseContext(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"final"}, order)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestAppendFinal_ContextDone(t *testing.T) {
+	resetPackage(&Lifo{})
+	pkgFinal = &Lifo{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	closed := false
+	AppendFinal(Fn(func() error {
+		time.Sleep(10 * time.Millisecond)
+		closed = true
+		return nil
+	}))
+
+	assert.NoError(t, CloseContext(ctx))
+	assert.True(t, closed)
+}
+
+func TestAppendFinal_NoReserve(t *testing.T) {
+	resetPackage(&Lifo{})
+	pkgFinal = &Lifo{}
+
+	closed := false
+	Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	// Without final closers, the whole timeout is left to the other closers.
+	assert.NoError(t, CloseWithTimeout(500*time.Millisecond))
+	assert.True(t, closed)
+}
+
+func TestAppendFinal_ReserveCapped(t *testing.T) {
+	resetPackage(&Lifo{})
+	pkgFinal = &Lifo{}
+
+	final, main := &ctxCloser{}, &ctxCloser{}
+	AppendFinal(final)
+	Append(main)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+
+	// The default reserve of 1s exceeds the timeout, so the other closers get half of it.
+	assert.NoError(t, CloseContext(ctx))
+
+	if assert.NotNil(t, main.ctx) {
+		got, ok := main.ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, deadline.Add(-200*time.Millisecond), got, 50*time.Millisecond)
+	}
+
+	assert.NotNil(t, final.ctx)
+}
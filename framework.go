@@ -0,0 +1,88 @@
+package shutdown
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/partyzanex/shutdown/health"
+)
+
+// HTTPFramework registers the server of a net/http based framework (chi, gin, echo and the like)
+// for a graceful shutdown with one call: it appends to the global closure a closer turning the checker
+// (health.Default if nil) not-ready, waiting for the propagation delay and then draining the server, see HTTPServer.
+// The delay gives the load balancers the time to observe the failing readiness probe and to stop routing
+// new requests to the instance; it is cut short if the shutdown context is done. The returned middleware
+// rejects the requests once the shutdown has started, see health.Checker.Middleware:
+//
+//	srv := &http.Server{Addr: ":8080"}
+//	router := chi.NewRouter()
+//	router.Use(shutdown.HTTPFramework(srv, checker, 5*time.Second, 10*time.Second))
+//	srv.Handler = router
+//
+// With echo, the middleware is added with e.Use(echo.WrapMiddleware(mw)), and the server is e.Server.
+// With gin, the server serves the engine, and the middleware is built with health.Checker.Reject.
+func HTTPFramework(
+	srv *http.Server, checker *health.Checker, propagation, drainTimeout time.Duration,
+) func(http.Handler) http.Handler {
+	if checker == nil {
+		checker = health.Default
+	}
+
+	appendPackage(Named("http framework", Sequence(
+		Named("health", checker),
+		propagationDelay(propagation),
+		HTTPServer(srv, drainTimeout),
+	)))
+
+	return checker.Middleware
+}
+
+// FastHTTPFramework registers the server of a fasthttp based framework, e.g. a *fiber.App, for a graceful
+// shutdown with one call: it appends to the global closure a closer turning the checker (health.Default if nil)
+// not-ready, waiting for the propagation delay as HTTPFramework does, and then shutting the server down,
+// see FastHTTP. The requests are rejected with a middleware
+// of the framework checking health.Checker.ShuttingDown, e.g. with fiber:
+//
+//	shutdown.FastHTTPFramework(app, checker, 5*time.Second)
+//	app.Use(func(c *fiber.Ctx) error {
+//		if checker.ShuttingDown() {
+//			c.Set(fiber.HeaderConnection, "close")
+//			return fiber.ErrServiceUnavailable
+//		}
+//
+//		return c.Next()
+//	})
+func FastHTTPFramework(srv FastHTTPServer, checker *health.Checker, propagation time.Duration) {
+	if checker == nil {
+		checker = health.Default
+	}
+
+	appendPackage(Named("fasthttp framework", Sequence(Named("health", checker), propagationDelay(propagation), FastHTTP(srv))))
+}
+
+// propagationDelay is a closer waiting for the not-ready state to propagate to the load balancers.
+type propagationDelay time.Duration
+
+// Close waits for the delay.
+func (d propagationDelay) Close() error {
+	return d.CloseContext(context.Background())
+}
+
+// CloseContext waits for the delay, or until the context is done: the rest of the sequence
+// still has to run then, so the interruption is not an error.
+func (d propagationDelay) CloseContext(ctx context.Context) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(d))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/partyzanex/shutdown/health"
+)
+
+func TestHTTPFramework(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	checker := health.New()
+	srv := &http.Server{ReadHeaderTimeout: time.Second}
+	mw := HTTPFramework(srv, checker, 0, time.Second)
+	srv.Handler = mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(l) }()
+
+	resp, err := http.Get("http://" + l.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	require.NoError(t, Close())
+	assert.ErrorIs(t, checker.Ready(), health.ErrShuttingDown)
+	assert.ErrorIs(t, <-served, http.ErrServerClosed)
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+// fastHTTPServerFunc adapts a function to the FastHTTPServer interface.
+type fastHTTPServerFunc func(ctx context.Context) error
+
+func (f fastHTTPServerFunc) ShutdownWithContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestFastHTTPFramework(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	checker := health.New()
+	ready := errors.New("not called")
+
+	var shutAfter time.Duration
+
+	start := time.Now()
+
+	FastHTTPFramework(fastHTTPServerFunc(func(context.Context) error {
+		ready = checker.Ready() // The checker is not-ready before the server shuts down
+		shutAfter = time.Since(start)
+		return nil
+	}), checker, 20*time.Millisecond)
+
+	require.NoError(t, Close())
+	assert.True(t, checker.ShuttingDown())
+	assert.ErrorIs(t, ready, health.ErrShuttingDown)
+	assert.GreaterOrEqual(t, shutAfter, 20*time.Millisecond) // The server waits for the propagation delay
+}
+
+func TestPropagationDelay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.NoError(t, propagationDelay(time.Hour).CloseContext(ctx))
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -3,61 +3,193 @@ package shutdown
 import (
 	"context"
 	"sync"
+	"time"
 
-	"go.uber.org/multierr"
+	"github.com/partyzanex/shutdown/internal/multierr"
 )
 
 // Group represents a collection of resources that need to be closed.
 type Group struct {
-	closers []Closer   // The list of resources to close.
-	mx      sync.Mutex // Mutex for thread safety.
+	progress
+
+	closers     []*Handle     // The list of resources to close.
+	mx          sync.Mutex    // Mutex for thread safety.
+	timeout     time.Duration // Timeout of Close, zero means no timeout.
+	concurrency int           // Maximum number of closers closed at the same time, zero means no limit.
+	tracer      Tracer        // Tracer of the close sequence, nil if not traced.
+}
+
+// NewGroup returns a Group configured by the options: WithCloseTimeout sets the timeout of Close,
+// WithConcurrency limits the number of closers closed at the same time, and WithTracer traces the close sequence.
+// The zero Group is ready to use without options.
+func NewGroup(opts ...Option) (*Group, error) {
+	o, err := newOptions(targetGroup, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Group{timeout: o.timeout, concurrency: o.concurrency, tracer: o.tracer}, nil
+}
+
+// Handle identifies a closer appended to a Group,
+// so that other closers can be ordered after it with AppendAfter.
+type Handle struct {
+	closer Closer    // The closer of the handle.
+	after  []*Handle // The handles that must be closed before this one.
 }
 
 // Append adds a new closer to the Group's list of closers.
 func (g *Group) Append(closer Closer) {
+	g.AppendAfter(closer)
+}
+
+// AppendAfter adds a new closer to the Group's list of closers, which is closed only
+// after the closers of the given handles are closed. The closers are still closed concurrently
+// otherwise, so mostly-parallel shutdowns can express the handful of orderings that matter.
+// Handles of other groups are ignored. Since a handle can only refer to already appended
+// closers, the constraints never form a cycle.
+//
+// Returns:
+// - The handle of the appended closer, to be used in further constraints.
+func (g *Group) AppendAfter(closer Closer, after ...*Handle) *Handle {
+	h := &Handle{closer: closer, after: after}
+
 	g.mx.Lock()         // Acquire the lock to ensure thread safety.
 	defer g.mx.Unlock() // Release the lock after the function finishes.
-	g.closers = append(g.closers, closer)
+	g.closers = append(g.closers, h)
+	g.added()
+
+	return h
 }
 
 // CloseContext attempts to close each resource in the Group with context support.
 // This allows external cancellation or timeout to be handled.
 func (g *Group) CloseContext(ctx context.Context) error {
+	return g.closeContext(ctx, nil)
+}
+
+// CloseEarly starts closing the resources in the background, like CloseContext, and surfaces the first error
+// as soon as it occurs, so the caller can start alerting before the whole parallel close finishes:
+//
+//	first, all := group.CloseEarly(ctx)
+//	if err, ok := <-first; ok {
+//		alert(err)
+//	}
+//	err := <-all
+//
+// The first channel receives the first error of a closer, or is closed without a value if all the closers succeed.
+// The all channel receives the combined errors, like the result of CloseContext, once all the closers are finished.
+func (g *Group) CloseEarly(ctx context.Context) (first, all <-chan error) {
+	firstErr := make(chan error, 1)
+	allErrs := make(chan error, 1)
+
+	go func() {
+		var once sync.Once
+
+		allErrs <- g.closeContext(ctx, func(err error) {
+			once.Do(func() { firstErr <- err })
+		})
+
+		once.Do(func() {}) // Ignore the late errors of the closers abandoned on timeout, so the channel can be closed
+		close(firstErr)
+	}()
+
+	return firstErr, allErrs
+}
+
+// closeContext closes the resources, calling onError, if not nil, with each error as soon as it occurs.
+func (g *Group) closeContext(ctx context.Context, onError func(error)) (err error) {
 	g.mx.Lock()         // Acquire the lock to ensure thread safety.
 	defer g.mx.Unlock() // Release the lock after the function finishes.
 
+	ctx, span := startSpan(ctx, g.tracer, SpanShutdown)
+	defer func() { span.End(err) }()
+
 	// Prepare a slice to store errors from all the closers.
 	var (
 		errs = make([]error, 0, len(g.closers))
 		mx   sync.Mutex // Local mutex for the error slice, to ensure thread safety while appending errors.
 	)
 
+	// Channels to signal when each closer finishes, awaited by the closers ordered after it.
+	dones := make(map[*Handle]chan struct{}, len(g.closers))
+	for _, h := range g.closers {
+		dones[h] = make(chan struct{})
+	}
+
+	// The closers not started yet are skipped if a closer panics under the PanicAbort policy.
+	// The closers get the parent context, so an abort does not cancel the running ones.
+	parent := ctx
+	ctx, abort := context.WithCancel(ctx)
+	defer abort()
+
+	var sem chan struct{} // Semaphore limiting the closers closed at the same time, nil if unlimited.
+	if g.concurrency > 0 {
+		sem = make(chan struct{}, g.concurrency)
+	}
+
 	wg := sync.WaitGroup{} // WaitGroup to wait for all closers to finish.
 	wg.Add(len(g.closers))
+	g.start(len(g.closers))
 
 	// Iterate through each closer in the Group.
-	for _, closer := range g.closers {
-		go func(c Closer) {
-			done := make(chan struct{}) // Channel to signal when the closer finishes.
+	for _, handle := range g.closers {
+		go func(h *Handle) {
+			defer wg.Done() // Signal that this goroutine is finished.
+
+			// Wait for the closers this one is ordered after. A closer aborting the sequence cancels ctx
+			// before it is done, so ctx is checked again once they are done.
+			if !waitHandles(ctx, h.after, dones) || ctx.Err() != nil {
+				return // The context is done, the closer is not started.
+			}
+
+			if sem != nil {
+				select {
+				case <-ctx.Done():
+					return // The context is done, the closer is not started.
+				case sem <- struct{}{}: // Acquire a slot.
+				}
+
+				if ctx.Err() != nil {
+					<-sem  // Release the slot.
+					return // The sequence is aborted while waiting for the slot.
+				}
+			}
+
+			done := dones[h]
 
 			// Inner goroutine to call the Close method of the resource.
 			go func() {
-				if err := c.Close(); err != nil {
+				var err error
+
+				aborted := callTraced(parent, g.tracer, h.closer, &err)
+				if err != nil {
 					mx.Lock()
 					errs = append(errs, err) // If there's an error, append it to the errs slice.
 					mx.Unlock()
+
+					if onError != nil {
+						onError(err) // Surface the error before the other closers finish.
+					}
+				}
+
+				if aborted {
+					abort() // Skip the closers not started yet.
 				}
 
+				g.done()
 				close(done) // Signal that the closer is done.
+
+				if sem != nil {
+					<-sem // Release the slot.
+				}
 			}()
 
 			select {
 			case <-ctx.Done(): // If the context is cancelled or times out.
 			case <-done: // Wait until the closer finishes.
 			}
-
-			wg.Done() // Signal that this goroutine is finished.
-		}(closer)
+		}(handle)
 	}
 
 	wg.Wait() // Wait until all closers are finished.
@@ -66,9 +198,29 @@ func (g *Group) CloseContext(ctx context.Context) error {
 	return multierr.Combine(errs...)
 }
 
-// Close attempts to close all resources in the Group without context support.
+// waitHandles waits until the closers of the given handles are closed.
+// It returns false if the context is done first.
+func waitHandles(ctx context.Context, handles []*Handle, dones map[*Handle]chan struct{}) bool {
+	for _, h := range handles {
+		done, ok := dones[h]
+		if !ok {
+			continue // The handle belongs to another group.
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-done:
+		}
+	}
+
+	return true
+}
+
+// Close attempts to close all resources in the Group without context support,
+// within the timeout set by NewGroup, if any.
 func (g *Group) Close() error {
-	return g.CloseContext(context.Background()) // Use a default background context.
+	return closeWithin(g, g.timeout)
 }
 
 // WithContext associates the Group instance with the provided context.
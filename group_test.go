@@ -3,11 +3,13 @@ package shutdown
 import (
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
 )
 
 type groupCloser struct {
@@ -78,3 +80,87 @@ func TestGroup_WithContext(t *testing.T) {
 		t.Fatalf("Expected to retrieve the original group from context, but got %v", closure)
 	}
 }
+
+func TestGroup_AppendAfter(t *testing.T) {
+	g := &Group{}
+
+	var (
+		mx    sync.Mutex
+		order []string
+	)
+
+	closer := func(name string, delay time.Duration) Closer {
+		return Fn(func() error {
+			time.Sleep(delay)
+			mx.Lock()
+			order = append(order, name)
+			mx.Unlock()
+			return nil
+		})
+	}
+
+	server := g.AppendAfter(closer("server", 30*time.Millisecond))
+	g.Append(closer("cache", 10*time.Millisecond))
+	g.AppendAfter(closer("db", 0), server)
+
+	start := time.Now()
+	assert.NoError(t, g.Close())
+	assert.Less(t, time.Since(start), 60*time.Millisecond)
+	assert.Equal(t, []string{"cache", "server", "db"}, order)
+}
+
+func TestGroup_AppendAfterContext(t *testing.T) {
+	g := &Group{}
+
+	blocked := g.AppendAfter(&groupCloser{delay: time.Second})
+	dependent := &groupCloser{}
+	g.AppendAfter(dependent, blocked)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, g.CloseContext(ctx))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&dependent.calls))
+}
+
+func TestGroup_CloseEarly(t *testing.T) {
+	errFirst := errors.New("first")
+	release := make(chan struct{})
+
+	g := &Group{}
+	g.Append(Fn(func() error { return errFirst }))
+	g.Append(Fn(func() error {
+		<-release
+		return errors.New("second")
+	}))
+
+	first, all := g.CloseEarly(context.Background())
+
+	select {
+	case err := <-first:
+		assert.Equal(t, errFirst, err)
+	case <-time.After(time.Second):
+		t.Fatal("Expected the first error before the other closers finish")
+	}
+
+	close(release)
+
+	err := <-all
+	assert.ErrorIs(t, err, errFirst)
+	assert.Len(t, multierr.Errors(err), 2)
+
+	_, ok := <-first
+	assert.False(t, ok, "Expected the first channel to be closed")
+}
+
+func TestGroup_CloseEarly_NoError(t *testing.T) {
+	g := &Group{}
+	g.Append(&mockCloser{})
+
+	first, all := g.CloseEarly(context.Background())
+
+	assert.NoError(t, <-all)
+
+	_, ok := <-first
+	assert.False(t, ok, "Expected the first channel to be closed without a value")
+}
@@ -0,0 +1,50 @@
+package shutdown
+
+import "context"
+
+// GRPCStopper is the part of *grpc.Server used by GRPCServer,
+// so that this package does not depend on gRPC.
+type GRPCStopper interface {
+	GracefulStop() // Stops accepting connections and waits for the pending RPCs
+	Stop()         // Closes all connections and cancels the pending RPCs
+}
+
+// GRPCServerCloser gracefully stops a gRPC server, escalating to a hard stop
+// once the shutdown context is done.
+type GRPCServerCloser struct {
+	srv GRPCStopper // The server to stop
+}
+
+// GRPCServer returns a closer for the given gRPC server:
+//
+//	go srv.Serve(lis)
+//	shutdown.Append(shutdown.GRPCServer(srv))
+func GRPCServer(srv GRPCStopper) *GRPCServerCloser {
+	return &GRPCServerCloser{srv: srv}
+}
+
+// CloseContext gracefully stops the server. If the context is done before the pending RPCs finish,
+// the server is stopped with Stop, which cancels them, and the context error is returned.
+func (g *GRPCServerCloser) CloseContext(ctx context.Context) error {
+	done := make(chan struct{}) // Closed once GracefulStop returns
+
+	go func() {
+		g.srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done: // All pending RPCs are finished.
+		return nil
+	case <-ctx.Done(): // If the context is cancelled or times out.
+		g.srv.Stop()
+		<-done // GracefulStop returns once Stop has closed the connections
+
+		return ctx.Err()
+	}
+}
+
+// Close gracefully stops the server without a deadline.
+func (g *GRPCServerCloser) Close() error {
+	return g.CloseContext(context.Background())
+}
@@ -0,0 +1,47 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGRPCServer mimics *grpc.Server: GracefulStop waits for the pending RPCs, which Stop cancels.
+type fakeGRPCServer struct {
+	pending  chan struct{} // Closed once the pending RPCs finish
+	once     sync.Once
+	stopped  bool
+	graceful bool
+}
+
+func (s *fakeGRPCServer) GracefulStop() {
+	<-s.pending
+	s.graceful = true
+}
+
+func (s *fakeGRPCServer) Stop() {
+	s.stopped = true
+	s.once.Do(func() { close(s.pending) })
+}
+
+func TestGRPCServer(t *testing.T) {
+	srv := &fakeGRPCServer{pending: make(chan struct{})}
+	close(srv.pending) // No pending RPCs
+
+	assert.NoError(t, GRPCServer(srv).Close())
+	assert.True(t, srv.graceful)
+	assert.False(t, srv.stopped)
+}
+
+func TestGRPCServer_Escalation(t *testing.T) {
+	srv := &fakeGRPCServer{pending: make(chan struct{})}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, GRPCServer(srv).CloseContext(ctx), context.DeadlineExceeded)
+	assert.True(t, srv.stopped)
+}
@@ -0,0 +1,141 @@
+// Package health reports the readiness of an application through its lifecycle:
+// components contribute readiness checks during startup, and the checker turns
+// not-ready as soon as the shutdown starts.
+package health
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// ErrShuttingDown is returned by Ready once the shutdown has started.
+var ErrShuttingDown = errors.New("health: shutting down")
+
+// check is a named readiness check.
+type check struct {
+	name string
+	fn   func() error
+}
+
+// Checker aggregates the readiness checks of the application components.
+// Register it as the first closer to close (e.g. the last appended to a Lifo),
+// so load balancers stop routing traffic before the resources are released.
+type Checker struct {
+	checks       []check      // The registered readiness checks, in registration order
+	mx           sync.RWMutex // Mutex for thread safety of the checks
+	shuttingDown atomic.Bool  // Whether the shutdown has started
+}
+
+// New creates a new Checker.
+func New() *Checker {
+	return &Checker{}
+}
+
+// AddReadinessCheck registers a readiness check of a component.
+// The application is ready only if all the checks return nil.
+func (c *Checker) AddReadinessCheck(name string, fn func() error) {
+	c.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer c.mx.Unlock() // Release the lock after the function finishes.
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Ready returns nil if the application is ready, otherwise the errors of the failed checks,
+// or ErrShuttingDown once the shutdown has started.
+func (c *Checker) Ready() error {
+	if c.shuttingDown.Load() {
+		return ErrShuttingDown
+	}
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	var errs error
+
+	for _, ch := range c.checks {
+		if err := ch.fn(); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%s: %w", ch.name, err))
+		}
+	}
+
+	return errs
+}
+
+// ServeHTTP serves the readiness endpoint: 200 if ready, 503 with the reasons otherwise.
+func (c *Checker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if err := c.Ready(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintln(w, err)
+
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// ShuttingDown reports whether the shutdown has started.
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Reject responds 503 with "Connection: close" and returns true once the shutdown has started,
+// steering the clients to other instances instead of racing the server shutdown.
+// It is the building block of the middlewares of the frameworks having their own middleware type, e.g. gin:
+//
+//	engine.Use(func(ctx *gin.Context) {
+//		if checker.Reject(ctx.Writer) {
+//			ctx.Abort()
+//			return
+//		}
+//
+//		ctx.Next()
+//	})
+func (c *Checker) Reject(w http.ResponseWriter) bool {
+	if !c.shuttingDown.Load() {
+		return false
+	}
+
+	w.Header().Set("Connection", "close")
+	http.Error(w, ErrShuttingDown.Error(), http.StatusServiceUnavailable)
+
+	return true
+}
+
+// Middleware wraps a net/http handler so that it rejects the requests once the shutdown has started, see Reject.
+// It is a middleware of the routers using the net/http handlers, e.g. chi.Router.Use(checker.Middleware),
+// and plugs into echo via echo.WrapMiddleware(checker.Middleware). The frameworks having their own
+// middleware type use Reject, or ShuttingDown if they are not net/http based, e.g. fiber.
+func (c *Checker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.Reject(w) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Close marks the application as shutting down, so the readiness endpoint reports 503.
+func (c *Checker) Close() error {
+	c.shuttingDown.Store(true)
+	return nil
+}
+
+// Default is the Checker used by the package-level functions.
+var Default = New()
+
+// AddReadinessCheck registers a readiness check on the Default checker.
+func AddReadinessCheck(name string, fn func() error) {
+	Default.AddReadinessCheck(name, fn)
+}
+
+// Middleware wraps the handler with the middleware of the Default checker.
+func Middleware(next http.Handler) http.Handler {
+	return Default.Middleware(next)
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker(t *testing.T) {
+	c := New()
+	dbErr := error(nil)
+
+	c.AddReadinessCheck("cache", func() error { return nil })
+	c.AddReadinessCheck("db", func() error { return dbErr })
+
+	assert.NoError(t, c.Ready())
+
+	dbErr = errors.New("not connected")
+	assert.EqualError(t, c.Ready(), "db: not connected")
+
+	dbErr = nil
+	assert.NoError(t, c.Close())
+	assert.ErrorIs(t, c.Ready(), ErrShuttingDown)
+}
+
+func TestChecker_ServeHTTP(t *testing.T) {
+	c := New()
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok\n", rec.Body.String())
+
+	c.AddReadinessCheck("db", func() error { return errors.New("not connected") })
+
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "db: not connected\n", rec.Body.String())
+}
+
+func TestAddReadinessCheck(t *testing.T) {
+	Default = New()
+	defer func() { Default = New() }()
+
+	AddReadinessCheck("db", func() error { return errors.New("not connected") })
+	assert.Error(t, Default.Ready())
+}
+
+func TestChecker_Middleware(t *testing.T) {
+	c := New()
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	assert.NoError(t, c.Close())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "close", rec.Header().Get("Connection"))
+}
+
+func TestChecker_Reject(t *testing.T) {
+	c := New()
+
+	rec := httptest.NewRecorder()
+	assert.False(t, c.Reject(rec))
+	assert.False(t, c.ShuttingDown())
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.NoError(t, c.Close())
+	assert.True(t, c.ShuttingDown())
+
+	rec = httptest.NewRecorder()
+	assert.True(t, c.Reject(rec))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "close", rec.Header().Get("Connection"))
+}
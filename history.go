@@ -0,0 +1,374 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// DurationStore persists the durations of previous shutdowns.
+type DurationStore interface {
+	Load() ([]time.Duration, error)       // Loads the recorded durations, oldest first
+	Save(durations []time.Duration) error // Saves the recorded durations
+}
+
+// CloserDurationStore is a DurationStore also persisting the close durations of the individual closers,
+// by their names. FileStore and EnvStore implement it.
+type CloserDurationStore interface {
+	DurationStore
+	LoadClosers() (map[string][]time.Duration, error)       // Loads the recorded durations by closer name, oldest first
+	SaveClosers(durations map[string][]time.Duration) error // Saves the recorded durations by closer name
+}
+
+// maxHistory is the maximum number of durations kept by the history closure, per closer.
+const maxHistory = 10
+
+// FileStore is a DurationStore persisting durations to a file, one duration per line.
+type FileStore string
+
+// Load reads the durations from the file. A missing file is treated as an empty history.
+func (f FileStore) Load() ([]time.Duration, error) {
+	data, err := os.ReadFile(string(f))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("shutdown: cannot read history: %w", err)
+	}
+
+	return parseDurations(strings.Fields(string(data)))
+}
+
+// Save writes the durations to the file atomically.
+func (f FileStore) Save(durations []time.Duration) error {
+	lines := make([]string, 0, len(durations))
+	for _, d := range durations {
+		lines = append(lines, d.String())
+	}
+
+	if err := writeFileAtomic(string(f), []byte(strings.Join(lines, "\n")+"\n")); err != nil {
+		return fmt.Errorf("shutdown: cannot write history: %w", err)
+	}
+
+	return nil
+}
+
+// LoadClosers reads the closer durations from the file named like the store with the ".closers" suffix,
+// one closer per line: its name, quoted as a Go string if needed, a tab and its comma-separated durations.
+// A missing file is treated as an empty history.
+func (f FileStore) LoadClosers() (map[string][]time.Duration, error) {
+	data, err := os.ReadFile(string(f) + ".closers")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("shutdown: cannot read history: %w", err)
+	}
+
+	durations := make(map[string][]time.Duration)
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+
+		name, values, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, fmt.Errorf("shutdown: invalid history line %q", line)
+		}
+
+		if strings.HasPrefix(name, `"`) {
+			if name, err = strconv.Unquote(name); err != nil {
+				return nil, fmt.Errorf("shutdown: invalid history line %q: %w", line, err)
+			}
+		}
+
+		if durations[name], err = parseDurations(strings.Split(values, ",")); err != nil {
+			return nil, err
+		}
+	}
+
+	return durations, nil
+}
+
+// SaveClosers writes the closer durations to the file named like the store with the ".closers" suffix atomically.
+func (f FileStore) SaveClosers(durations map[string][]time.Duration) error {
+	names := make([]string, 0, len(durations))
+	for name := range durations {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var b strings.Builder
+
+	for _, name := range names {
+		values := make([]string, 0, len(durations[name]))
+		for _, d := range durations[name] {
+			values = append(values, d.String())
+		}
+
+		b.WriteString(historyName(name) + "\t" + strings.Join(values, ",") + "\n")
+	}
+
+	if err := writeFileAtomic(string(f)+".closers", []byte(b.String())); err != nil {
+		return fmt.Errorf("shutdown: cannot write history: %w", err)
+	}
+
+	return nil
+}
+
+// historyName returns the name as written to the history file: quoted if it would break the line format.
+func historyName(name string) string {
+	if strings.ContainsAny(name, "\t\r\n") || strings.HasPrefix(name, `"`) || name != strings.TrimSpace(name) {
+		return strconv.Quote(name)
+	}
+
+	return name
+}
+
+// EnvStore is a read-only DurationStore loading comma-separated durations
+// from an environment variable, e.g. provided by the deployment tooling.
+type EnvStore string
+
+// Load reads the durations from the environment variable.
+func (e EnvStore) Load() ([]time.Duration, error) {
+	value := os.Getenv(string(e))
+	if value == "" {
+		return nil, nil
+	}
+
+	return parseDurations(strings.Split(value, ","))
+}
+
+// Save does nothing, since the environment cannot be persisted.
+func (e EnvStore) Save([]time.Duration) error {
+	return nil
+}
+
+// LoadClosers reads the closer durations from the environment variable named like the store
+// with the "_CLOSERS" suffix, e.g. SHUTDOWN_HISTORY_CLOSERS="db=1s,2s;cache=300ms".
+func (e EnvStore) LoadClosers() (map[string][]time.Duration, error) {
+	value := os.Getenv(string(e) + "_CLOSERS")
+	if value == "" {
+		return nil, nil
+	}
+
+	durations := make(map[string][]time.Duration)
+
+	for _, closer := range strings.Split(value, ";") {
+		name, values, ok := strings.Cut(closer, "=")
+		if !ok {
+			return nil, fmt.Errorf("shutdown: invalid history closer %q", closer)
+		}
+
+		d, err := parseDurations(strings.Split(values, ","))
+		if err != nil {
+			return nil, err
+		}
+
+		durations[strings.TrimSpace(name)] = d
+	}
+
+	return durations, nil
+}
+
+// SaveClosers does nothing, since the environment cannot be persisted.
+func (e EnvStore) SaveClosers(map[string][]time.Duration) error {
+	return nil
+}
+
+func parseDurations(values []string) ([]time.Duration, error) {
+	durations := make([]time.Duration, 0, len(values))
+
+	for _, value := range values {
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("shutdown: invalid history duration %q: %w", value, err)
+		}
+
+		durations = append(durations, d)
+	}
+
+	return durations, nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name()) // Clean up if anything goes wrong, fails harmlessly after the rename
+
+	if _, err = tmp.Write(data); err != nil {
+		return multierr.Append(err, tmp.Close())
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// historyClosure records the duration of every close sequence of the wrapped Closure.
+type historyClosure struct {
+	decorator               // The wrapped closure
+	store     DurationStore // The store of the recorded durations
+}
+
+// WithHistory wraps the closure so that the duration of its close sequence
+// is recorded to the store, keeping the last few durations. If the store is a CloserDurationStore,
+// the durations of the closers of the sequence are recorded as well, by their names.
+// Use CheckHistory at startup to verify the grace period against the history,
+// and HistoryBudgets to pre-allocate the timeouts of the closers.
+func WithHistory(closure Closure, store DurationStore) Closure {
+	return &historyClosure{decorator: decorator{closure}, store: store}
+}
+
+// CloseContext closes the wrapped closure and records the durations of the closing.
+func (h *historyClosure) CloseContext(ctx context.Context) error {
+	rec := &recorder{parent: recorderFromContext(ctx)} // Also passes the results to the package-level report
+
+	start := time.Now()
+	err := h.Closure.CloseContext(context.WithValue(ctx, recorderKey{}, rec))
+
+	_, recordErr := recordDuration(h.store, time.Since(start), maxHistory)
+
+	if store, ok := h.store.(CloserDurationStore); ok {
+		recordErr = multierr.Append(recordErr, recordCloserDurations(store, rec.seal(), maxHistory))
+	}
+
+	return multierr.Append(err, recordErr)
+}
+
+// recordDuration appends the duration to the durations in the store, keeping the last limit ones.
+// It returns the recorded durations, oldest first.
+func recordDuration(store DurationStore, d time.Duration, limit int) ([]time.Duration, error) {
+	durations, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	durations = append(durations, d)
+	if len(durations) > limit {
+		durations = durations[len(durations)-limit:]
+	}
+
+	return durations, store.Save(durations)
+}
+
+// recordCloserDurations appends the durations of the closed closers to the ones in the store,
+// keeping the last limit ones per closer. The skipped closers are not recorded;
+// of the closers sharing a name, the longest duration is.
+func recordCloserDurations(store CloserDurationStore, results []Result, limit int) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	durations, err := store.LoadClosers()
+	if err != nil {
+		return err
+	}
+
+	if durations == nil {
+		durations = make(map[string][]time.Duration, len(results))
+	}
+
+	run := make(map[string]time.Duration, len(results)) // The durations of this run
+	for _, result := range results {
+		if d, ok := run[result.Name]; !result.Skipped && (!ok || result.Duration > d) {
+			run[result.Name] = result.Duration
+		}
+	}
+
+	for name, d := range run {
+		recorded := append(durations[name], d)
+		if len(recorded) > limit {
+			recorded = recorded[len(recorded)-limit:]
+		}
+
+		durations[name] = recorded
+	}
+
+	return store.SaveClosers(durations)
+}
+
+// Close closes the wrapped closure without context support and records the duration.
+func (h *historyClosure) Close() error {
+	return h.CloseContext(context.Background())
+}
+
+// WithContext associates the history closure with the given context.
+func (h *historyClosure) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, h)
+}
+
+// CheckHistory predicts whether the given grace period suffices for the shutdown,
+// based on the longest duration recorded in the store. If it doesn't, a warning is logged
+// and the longest recorded duration is returned, so callers can adjust their budget.
+func CheckHistory(store DurationStore, grace time.Duration, logger Logger) (time.Duration, error) {
+	durations, err := store.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	var longest time.Duration
+
+	for _, d := range durations {
+		if d > longest {
+			longest = d
+		}
+	}
+
+	if longest > grace {
+		logger.Msgf("Shutdown took up to %s previously, which exceeds the grace period of %s", longest, grace)
+	}
+
+	return longest, nil
+}
+
+// HistoryBudgets returns the time budgets of the closers recorded in the store, to be pre-allocated
+// as their own timeouts: the longest recorded duration of each closer multiplied by headroom,
+// which is at least 1. A closer missing from the history gets a zero budget, that is no own timeout:
+//
+//	budgets, err := shutdown.HistoryBudgets(store, 1.5)
+//	closure.Append(db, shutdown.WithName("db"), shutdown.WithTimeout(budgets["db"]))
+func HistoryBudgets(store CloserDurationStore, headroom float64) (map[string]time.Duration, error) {
+	durations, err := store.LoadClosers()
+	if err != nil {
+		return nil, err
+	}
+
+	if headroom < 1 {
+		headroom = 1
+	}
+
+	budgets := make(map[string]time.Duration, len(durations))
+
+	for name, recorded := range durations {
+		var longest time.Duration
+
+		for _, d := range recorded {
+			if d > longest {
+				longest = d
+			}
+		}
+
+		budgets[name] = time.Duration(float64(longest) * headroom)
+	}
+
+	return budgets, nil
+}
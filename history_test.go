@@ -0,0 +1,114 @@
+package shutdown
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHistory(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "history"))
+
+	for i := 0; i < maxHistory+2; i++ {
+		closure := WithHistory(&Lifo{}, store)
+		closure.Append(Fn(func() error {
+			time.Sleep(time.Millisecond)
+			return nil
+		}))
+		require.NoError(t, closure.Close())
+	}
+
+	durations, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, durations, maxHistory)
+
+	for _, d := range durations {
+		assert.GreaterOrEqual(t, d, time.Millisecond)
+	}
+}
+
+func TestWithHistory_Closers(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "history"))
+
+	for i := 0; i < 2; i++ {
+		closure := WithHistory(&Lifo{}, store)
+		closure.Append(Named("db", Fn(func() error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})))
+		closure.Append(Named("cache", &mockCloser{}))
+		require.NoError(t, closure.Close())
+	}
+
+	durations, err := store.LoadClosers()
+	require.NoError(t, err)
+	assert.Len(t, durations, 2)
+	assert.Len(t, durations["cache"], 2)
+
+	if assert.Len(t, durations["db"], 2) {
+		assert.GreaterOrEqual(t, durations["db"][0], 10*time.Millisecond)
+	}
+
+	budgets, err := HistoryBudgets(store, 2)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, budgets["db"], 20*time.Millisecond)
+	assert.Zero(t, budgets["missing"])
+}
+
+func TestHistoryBudgets_Env(t *testing.T) {
+	t.Setenv("SHUTDOWN_HISTORY_CLOSERS", "db=1s,3s; cache=200ms")
+
+	budgets, err := HistoryBudgets(EnvStore("SHUTDOWN_HISTORY"), 0.5) // The headroom is at least 1
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"db": 3 * time.Second, "cache": 200 * time.Millisecond}, budgets)
+}
+
+func TestCheckHistory(t *testing.T) {
+	t.Setenv("SHUTDOWN_HISTORY", "1s, 5s,2s")
+
+	logger := &mockLogger{}
+
+	longest, err := CheckHistory(EnvStore("SHUTDOWN_HISTORY"), 3*time.Second, logger)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, longest)
+	assert.Equal(t, "Shutdown took up to 5s previously, which exceeds the grace period of 3s", getLastLoggedMessage(logger))
+
+	logger = &mockLogger{}
+
+	_, err = CheckHistory(EnvStore("SHUTDOWN_HISTORY"), 10*time.Second, logger)
+	require.NoError(t, err)
+	assert.Empty(t, logger.messages)
+}
+
+func TestFileStore_Missing(t *testing.T) {
+	durations, err := FileStore(filepath.Join(t.TempDir(), "missing")).Load()
+	assert.NoError(t, err)
+	assert.Empty(t, durations)
+}
+
+func TestEnvStore_Invalid(t *testing.T) {
+	t.Setenv("SHUTDOWN_HISTORY", "soon")
+
+	_, err := EnvStore("SHUTDOWN_HISTORY").Load()
+	assert.Error(t, err)
+}
+
+func TestFileStore_ClosersNames(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "history"))
+	durations := map[string][]time.Duration{
+		"db":              {time.Second},
+		"tab\tand\nline":  {2 * time.Second},
+		`"quoted"`:        {3 * time.Second},
+		" padded ":        {4 * time.Second},
+		"cache, sessions": {5 * time.Second, 6 * time.Second},
+	}
+
+	require.NoError(t, store.SaveClosers(durations))
+
+	loaded, err := store.LoadClosers()
+	require.NoError(t, err)
+	assert.Equal(t, durations, loaded)
+}
@@ -0,0 +1,56 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// HTTPServerCloser gracefully closes a *http.Server: the server stops accepting new connections,
+// the in-flight requests are drained, and the remaining connections are closed forcibly
+// once the drain timeout or the shutdown context expires.
+type HTTPServerCloser struct {
+	srv   *http.Server  // The server to close
+	drain time.Duration // Maximum duration of the drain, zero means no own timeout
+}
+
+// HTTPServer returns a closer for the given server, replacing the boilerplate
+// around http.Server.Shutdown:
+//
+//	go srv.ListenAndServe()
+//	shutdown.Append(shutdown.HTTPServer(srv, 10*time.Second))
+//
+// The drain timeout bounds the graceful part of the shutdown within the shutdown context;
+// zero means the drain lasts as long as the shutdown context allows.
+func HTTPServer(srv *http.Server, drainTimeout time.Duration) *HTTPServerCloser {
+	return &HTTPServerCloser{srv: srv, drain: drainTimeout}
+}
+
+// CloseContext shuts the server down, which closes its listeners and idle connections
+// and waits for the active connections to become idle. If the drain timeout or the context
+// expires first, the remaining connections are closed with http.Server.Close.
+func (h *HTTPServerCloser) CloseContext(ctx context.Context) error {
+	drainCtx := ctx
+
+	if h.drain > 0 {
+		var cancel context.CancelFunc
+
+		drainCtx, cancel = context.WithTimeout(ctx, h.drain)
+		defer cancel()
+	}
+
+	err := h.srv.Shutdown(drainCtx)
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return multierr.Append(err, h.srv.Close()) // Close the connections that did not drain in time
+	}
+
+	return err
+}
+
+// Close shuts the server down within the drain timeout, see CloseContext.
+func (h *HTTPServerCloser) Close() error {
+	return h.CloseContext(context.Background())
+}
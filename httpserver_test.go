@@ -0,0 +1,85 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startHTTPServer serves the handler on a local listener and returns the server and its URL.
+func startHTTPServer(t *testing.T, handler http.Handler) (*http.Server, string) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: time.Second}
+
+	go func() { _ = srv.Serve(l) }()
+
+	return srv, "http://" + l.Addr().String()
+}
+
+func TestHTTPServer(t *testing.T) {
+	started := make(chan struct{})
+
+	srv, url := startHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	status := make(chan int, 1)
+
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			status <- 0
+			return
+		}
+
+		_ = resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+
+	<-started
+	assert.NoError(t, HTTPServer(srv, time.Second).Close())
+	assert.Equal(t, http.StatusNoContent, <-status) // The in-flight request is drained
+
+	_, err := http.Get(url)
+	assert.Error(t, err)
+}
+
+func TestHTTPServer_DrainTimeout(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+
+	srv, url := startHTTPServer(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	failed := make(chan error, 1)
+
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+
+		failed <- err
+	}()
+
+	<-started
+
+	start := time.Now()
+	err := HTTPServer(srv, 20*time.Millisecond).CloseContext(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Error(t, <-failed) // The stuck connection is closed forcibly
+}
@@ -0,0 +1,63 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+)
+
+// InFlight tracks in-flight operations (requests, packets, jobs) so that
+// the shutdown can wait for their completion before closing the underlying resources.
+// Unlike a sync.WaitGroup, operations may be added while Wait is waiting.
+type InFlight struct {
+	mx    sync.Mutex    // Mutex for thread safety
+	count int           // Number of the operations in progress
+	idle  chan struct{} // Closed once no operation is in progress, nil if none is
+}
+
+// Add registers a new in-flight operation.
+func (f *InFlight) Add() {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	if f.count == 0 {
+		f.idle = make(chan struct{})
+	}
+
+	f.count++
+}
+
+// Done marks an in-flight operation as completed.
+func (f *InFlight) Done() {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	if f.count == 0 {
+		panic("shutdown: InFlight.Done called without Add")
+	}
+
+	f.count--
+
+	if f.count == 0 {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// Wait blocks until all in-flight operations, including the ones added while waiting,
+// are completed or the context is done. It returns the context error if the context is done first.
+func (f *InFlight) Wait(ctx context.Context) error {
+	f.mx.Lock()
+	idle := f.idle // Closed once the operations are completed
+	f.mx.Unlock()
+
+	if idle == nil {
+		return nil // No operation is in progress.
+	}
+
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out.
+		return ctx.Err()
+	case <-idle: // All operations are completed.
+		return nil
+	}
+}
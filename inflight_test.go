@@ -0,0 +1,61 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInFlight_Wait(t *testing.T) {
+	f := &InFlight{}
+	f.Add()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		f.Done()
+	}()
+
+	assert.NoError(t, f.Wait(context.Background()))
+}
+
+func TestInFlight_WaitContext(t *testing.T) {
+	f := &InFlight{}
+	f.Add()
+	defer f.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, f.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestInFlight_AddWhileWaiting(t *testing.T) {
+	f := &InFlight{}
+	f.Add()
+
+	released := make(chan struct{})
+
+	go func() {
+		f.Add() // Added while Wait is waiting
+		f.Done()
+		time.Sleep(10 * time.Millisecond)
+		close(released)
+		f.Done()
+	}()
+
+	assert.NoError(t, f.Wait(context.Background()))
+
+	select {
+	case <-released:
+	default:
+		t.Fatal("Wait returned before the operations completed")
+	}
+
+	assert.NoError(t, f.Wait(context.Background())) // Nothing in flight
+}
+
+func TestInFlight_DoneWithoutAdd(t *testing.T) {
+	assert.Panics(t, func() { (&InFlight{}).Done() })
+}
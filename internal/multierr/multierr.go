@@ -0,0 +1,22 @@
+//go:build !shutdown_stdlib
+
+// Package multierr combines errors with go.uber.org/multierr, or with the standard library
+// when built with the shutdown_stdlib tag, for users with strict dependency policies.
+package multierr
+
+import "go.uber.org/multierr"
+
+// Append appends the right error to the left one, ignoring nil errors.
+func Append(left, right error) error {
+	return multierr.Append(left, right)
+}
+
+// Combine combines the errors into one, ignoring nil errors.
+func Combine(errs ...error) error {
+	return multierr.Combine(errs...)
+}
+
+// Errors returns the errors combined into err.
+func Errors(err error) []error {
+	return multierr.Errors(err)
+}
@@ -0,0 +1,20 @@
+package multierr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombine(t *testing.T) {
+	first, second, third := errors.New("first"), errors.New("second"), errors.New("third")
+
+	assert.NoError(t, Combine(nil, nil))
+	assert.Equal(t, first, Append(nil, first))
+
+	err := Append(Combine(first, nil, second), third)
+	assert.Equal(t, []error{first, second, third}, Errors(err))
+	assert.ErrorIs(t, err, second)
+	assert.Nil(t, Errors(nil))
+}
@@ -0,0 +1,44 @@
+//go:build shutdown_stdlib
+
+// Package multierr combines errors with go.uber.org/multierr, or with the standard library
+// when built with the shutdown_stdlib tag, for users with strict dependency policies.
+package multierr
+
+import "errors"
+
+// Append appends the right error to the left one, ignoring nil errors.
+func Append(left, right error) error {
+	return Combine(left, right)
+}
+
+// Combine combines the errors into one, ignoring nil errors.
+// The errors combined before are flattened, as go.uber.org/multierr does.
+func Combine(errs ...error) error {
+	var flat []error
+
+	for _, err := range errs {
+		flat = append(flat, Errors(err)...)
+	}
+
+	switch len(flat) {
+	case 0:
+		return nil
+	case 1:
+		return flat[0]
+	default:
+		return errors.Join(flat...)
+	}
+}
+
+// Errors returns the errors combined into err.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+
+	return []error{err}
+}
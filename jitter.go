@@ -0,0 +1,58 @@
+package shutdown
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// maxJitterShare is the largest share of the time left until the deadline of the close sequence
+// spent on the start jitter, so that the jitter never eats the budget of the closers.
+const maxJitterShare = 0.1
+
+// jitterClosure delays the close sequence of the wrapped Closure by a random duration.
+type jitterClosure struct {
+	decorator               // The wrapped closure
+	maxDelay  time.Duration // The maximum delay before closing
+}
+
+// WithStartJitter wraps the closure so that its close sequence starts after a random
+// delay in [0, maxDelay). When an orchestrator signals many instances simultaneously,
+// the jitter prevents them from disconnecting from shared dependencies at the same instant.
+// If the context has a deadline, the delay is capped to a tenth of the time left until it,
+// and the delay is interrupted if the context is done.
+func WithStartJitter(closure Closure, maxDelay time.Duration) Closure {
+	return &jitterClosure{decorator: decorator{closure}, maxDelay: maxDelay}
+}
+
+// CloseContext waits for a random delay and then closes the wrapped closure.
+func (j *jitterClosure) CloseContext(ctx context.Context) error {
+	maxDelay := j.maxDelay
+	if deadline, ok := ctx.Deadline(); ok {
+		if limit := time.Duration(float64(time.Until(deadline)) * maxJitterShare); limit < maxDelay {
+			maxDelay = limit
+		}
+	}
+
+	if maxDelay > 0 {
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(maxDelay)))) //nolint:gosec // jitter does not need a secure random
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done(): // Start closing right away, the wrapped closure handles the context error.
+		case <-timer.C:
+		}
+	}
+
+	return j.Closure.CloseContext(ctx)
+}
+
+// Close waits for a random delay and then closes the wrapped closure without context support.
+func (j *jitterClosure) Close() error {
+	return j.CloseContext(context.Background())
+}
+
+// WithContext associates the jitter closure with the given context.
+func (j *jitterClosure) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, j)
+}
@@ -0,0 +1,48 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStartJitter(t *testing.T) {
+	lifo := &Lifo{}
+	closure := WithStartJitter(lifo, 20*time.Millisecond)
+
+	closed := false
+	closure.Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	start := time.Now()
+	assert.NoError(t, closure.Close())
+	assert.True(t, closed)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWithStartJitter_Context(t *testing.T) {
+	closure := WithStartJitter(&Lifo{}, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// The delay is capped to a tenth of the time left until the deadline.
+	start := time.Now()
+	assert.NoError(t, closure.CloseContext(ctx))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start = time.Now()
+	assert.NoError(t, closure.CloseContext(ctx))
+	assert.Less(t, time.Since(start), time.Second)
+
+	extracted, ok := ClosureFromContext(closure.WithContext(context.Background()))
+	assert.True(t, ok)
+	assert.Equal(t, closure, extracted)
+}
@@ -0,0 +1,74 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrLeakedFiles is returned (wrapped) by the check of VerifyOpenFiles when files are left open.
+var ErrLeakedFiles = errors.New("shutdown: files left open")
+
+// runtimeFiles matches the descriptors opened by the Go runtime itself, e.g. the network poller.
+const runtimeFiles = "anon_inode:*"
+
+// VerifyOpenFiles snapshots the open file descriptors (including sockets) and registers a Verify check
+// reporting the ones opened since then and still open after the closers finish, catching the resources
+// that were never registered with the package at all. The descriptors whose target matches any of the
+// allow patterns (see filepath.Match), e.g. "/var/log/*" or "socket:*", are not reported.
+// It returns an error if the open descriptors cannot be listed on this platform (only Linux is supported).
+func VerifyOpenFiles(allow ...string) error {
+	for _, pattern := range allow {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("shutdown: invalid allow pattern %q: %w", pattern, err)
+		}
+	}
+
+	baseline, err := openFiles()
+	if err != nil {
+		return err
+	}
+
+	allow = append(allow, runtimeFiles)
+
+	Verify(func(context.Context) error {
+		files, err := openFiles()
+		if err != nil {
+			return err
+		}
+
+		var leaked []string
+
+		for fd, target := range files {
+			if baseline[fd] == target || allowed(target, allow) {
+				continue
+			}
+
+			leaked = append(leaked, fmt.Sprintf("%d (%s)", fd, target))
+		}
+
+		if len(leaked) == 0 {
+			return nil
+		}
+
+		sort.Strings(leaked)
+
+		return fmt.Errorf("%w: %s", ErrLeakedFiles, strings.Join(leaked, ", "))
+	})
+
+	return nil
+}
+
+// allowed reports whether the target matches any of the patterns.
+func allowed(target string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, target); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package shutdown
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyOpenFiles(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("listing open files is supported on Linux only")
+	}
+
+	defer func() { pkgVerifiers = nil }()
+
+	dir := t.TempDir()
+
+	require.NoError(t, VerifyOpenFiles(filepath.Join(dir, "allowed*")))
+
+	registered, err := os.Create(filepath.Join(dir, "registered"))
+	require.NoError(t, err)
+
+	leaked, err := os.Create(filepath.Join(dir, "leaked"))
+	require.NoError(t, err)
+
+	defer leaked.Close()
+
+	allowedFile, err := os.Create(filepath.Join(dir, "allowed.log"))
+	require.NoError(t, err)
+
+	defer allowedFile.Close()
+
+	resetPackage(&Lifo{})
+	Append(registered)
+
+	err = Close()
+	assert.ErrorIs(t, err, ErrUnclean)
+	assert.ErrorIs(t, err, ErrLeakedFiles)
+	assert.Contains(t, err.Error(), leaked.Name())
+	assert.NotContains(t, err.Error(), registered.Name())
+	assert.NotContains(t, err.Error(), allowedFile.Name())
+}
+
+func TestVerifyOpenFiles_InvalidPattern(t *testing.T) {
+	assert.Error(t, VerifyOpenFiles("["))
+	assert.Empty(t, pkgVerifiers)
+}
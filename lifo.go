@@ -3,14 +3,31 @@ package shutdown
 import (
 	"context"
 	"sync"
+	"time"
 
-	"go.uber.org/multierr"
+	"github.com/partyzanex/shutdown/internal/multierr"
 )
 
 // Lifo represents a stack (Last-In, First-Out) of resources that need to be closed.
 type Lifo struct {
-	stack []Closer   // The stack of resources to close.
-	mx    sync.Mutex // Mutex for thread safety.
+	progress
+
+	stack   []Closer      // The stack of resources to close.
+	mx      sync.Mutex    // Mutex for thread safety.
+	timeout time.Duration // Timeout of Close, zero means no timeout.
+	tracer  Tracer        // Tracer of the close sequence, nil if not traced.
+}
+
+// NewLifo returns a Lifo configured by the options: WithCloseTimeout sets the timeout of Close,
+// and WithTracer traces the close sequence.
+// The zero Lifo is ready to use without options.
+func NewLifo(opts ...Option) (*Lifo, error) {
+	o, err := newOptions(targetLifo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Lifo{timeout: o.timeout, tracer: o.tracer}, nil
 }
 
 // Append pushes a new closer onto the Lifo stack.
@@ -18,22 +35,31 @@ func (l *Lifo) Append(closer Closer) {
 	l.mx.Lock()         // Acquire the lock to ensure thread safety.
 	defer l.mx.Unlock() // Release the lock after the function finishes.
 	l.stack = append(l.stack, closer)
+	l.added()
 }
 
 // CloseContext attempts to close each resource in the Lifo stack with context support.
 // It starts closing from the top of the stack (Last-In resource).
-func (l *Lifo) CloseContext(ctx context.Context) error {
+func (l *Lifo) CloseContext(ctx context.Context) (err error) {
 	l.mx.Lock()         // Acquire the lock to ensure thread safety.
 	defer l.mx.Unlock() // Release the lock after the function finishes.
 
+	ctx, span := startSpan(ctx, l.tracer, SpanShutdown)
+	defer func() { span.End(err) }()
+
 	var errs error // This will store the accumulated errors.
 
+	l.start(len(l.stack))
+
 	// Start from the top of the stack and iterate in reverse order.
 	for i := len(l.stack) - 1; i >= 0; i-- {
+		closer := l.stack[i]
 		next := make(chan struct{}) // Channel to signal completion of the closer.
+		abort := false
 
 		go func() {
-			callClose(l.stack[i], &errs) // Call the close function for the current closer.
+			abort = callTraced(ctx, l.tracer, closer, &errs) // Call the close function for the current closer.
+			l.done()
 			close(next)
 		}()
 
@@ -41,15 +67,19 @@ func (l *Lifo) CloseContext(ctx context.Context) error {
 		case <-ctx.Done(): // If the context is cancelled or times out.
 			return multierr.Append(errs, ctx.Err()) // Return the accumulated errors and the context error.
 		case <-next: // Move to the next closer in the stack after the current one finishes.
+			if abort {
+				return errs // A closer panicked under the PanicAbort policy.
+			}
 		}
 	}
 
 	return errs // Return the accumulated errors.
 }
 
-// Close attempts to close all resources in the Lifo stack without context support.
+// Close attempts to close all resources in the Lifo stack without context support,
+// within the timeout set by NewLifo, if any.
 func (l *Lifo) Close() error {
-	return l.CloseContext(context.Background()) // Using a background context which will never be cancelled.
+	return closeWithin(l, l.timeout)
 }
 
 // WithContext embeds the Lifo instance into the given context.
@@ -0,0 +1,84 @@
+package shutdown
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrTooManyClosers is returned by TryAppend when the global closure already holds the maximum
+// number of closers set with WithMaxClosers.
+var ErrTooManyClosers = errors.New("shutdown: too many closers")
+
+var (
+	pkgMaxClosers int          // Maximum number of closers of the global closure, zero means no limit
+	pkgRejected   atomic.Int64 // Number of closers rejected because of pkgMaxClosers
+)
+
+// pendingCounter is implemented by the closures counting their registered closers.
+type pendingCounter interface {
+	Pending() int // Returns the number of closers that are not closed yet
+}
+
+// WithMaxClosers caps the number of closers registered in the global closure; zero means no limit.
+// The cap protects a long-running process from code that appends per request to the process-level
+// closure by mistake, which grows it unboundedly until the shutdown takes minutes.
+// Registrations beyond the cap are rejected: TryAppend returns ErrTooManyClosers,
+// Append drops the closer, logging it with the logger given to the signal helpers or the standard logger,
+// and both count it in Rejected.
+// The cap is enforced only if the global closure exposes Pending, as Lifo, Fifo and Group do.
+func WithMaxClosers(n int) Option {
+	return func(o *options) error {
+		if err := o.applies("WithMaxClosers", targetInit); err != nil {
+			return err
+		}
+
+		if n < 0 {
+			return fmt.Errorf("%w: negative max closers %d", ErrInvalidOption, n)
+		}
+
+		o.maxClosers = n
+
+		return nil
+	}
+}
+
+// TryAppend adds a new closer to the global closure,
+// or returns ErrTooManyClosers if the cap set with WithMaxClosers is reached.
+func TryAppend(closer Closer) error {
+	checkStrict(packageEntry(closer, callerPackage(1)))
+
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+
+	if err := checkCapacity(); err != nil {
+		return err
+	}
+
+	pkgClosure.Append(closer) // Appending the closer
+
+	return nil
+}
+
+// Rejected returns the number of closers rejected because the cap set with WithMaxClosers was reached.
+// A growing value points to code appending to the global closure in a loop.
+func Rejected() int {
+	return int(pkgRejected.Load())
+}
+
+// checkCapacity returns ErrTooManyClosers and counts the rejection if the global closure is full.
+// It must be called with mu held.
+func checkCapacity() error {
+	if pkgMaxClosers <= 0 {
+		return nil
+	}
+
+	c, ok := pkgClosure.(pendingCounter)
+	if !ok || c.Pending() < pkgMaxClosers {
+		return nil
+	}
+
+	pkgRejected.Add(1)
+
+	return fmt.Errorf("%w: limit of %d reached", ErrTooManyClosers, pkgMaxClosers)
+}
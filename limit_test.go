@@ -0,0 +1,38 @@
+package shutdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMaxClosers(t *testing.T) {
+	lifo := &Lifo{}
+	resetPackage(lifo)
+	require.NoError(t, Init(WithMaxClosers(2)))
+
+	defer func() {
+		require.NoError(t, Init(WithMaxClosers(0)))
+		resetPackage(&Lifo{})
+	}()
+
+	rejected := Rejected()
+	logger := &mockLogger{}
+	pkgLogger = logger
+
+	require.NoError(t, TryAppend(&mockCloser{}))
+	Append(&mockCloser{})
+
+	assert.ErrorIs(t, TryAppend(&mockCloser{}), ErrTooManyClosers)
+	Append(Named("per-request", &mockCloser{}))
+	assert.Equal(t, "Closer per-request is not appended and will not be closed: shutdown: too many closers: limit of 2 reached",
+		getLastLoggedMessage(logger))
+
+	assert.Equal(t, 2, lifo.Pending())
+	assert.Equal(t, rejected+2, Rejected())
+
+	require.NoError(t, Init(WithMaxClosers(0)))
+	assert.NoError(t, TryAppend(&mockCloser{}))
+	assert.Equal(t, 3, lifo.Pending())
+}
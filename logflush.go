@@ -0,0 +1,48 @@
+package shutdown
+
+import (
+	"context"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+var pkgLogFlushers []func(ctx context.Context) error // Flushers of the logging backends, run last
+
+// RegisterLogFlusher registers a flusher of a logging backend, which the package-level CloseContext
+// invokes absolutely last: after the final closers and after the notifier is told about the completion,
+// so that everything logged about the shutdown itself reaches the backend before the process exits.
+// The flushers run in registration order. If the shutdown context is already done, they get a fresh
+// context limited by the final reserve.
+func RegisterLogFlusher(flush func(ctx context.Context) error) {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+	pkgLogFlushers = append(pkgLogFlushers, flush)
+}
+
+// flushLogs runs the registered log flushers. The caller must hold mu.
+func flushLogs(ctx context.Context) error {
+	if len(pkgLogFlushers) == 0 {
+		return nil
+	}
+
+	ctx, cancel := reserveContext(ctx)
+	defer cancel()
+
+	var errs error
+
+	for _, flush := range pkgLogFlushers {
+		errs = multierr.Append(errs, flush(ctx))
+	}
+
+	return errs
+}
+
+// reserveContext returns a fresh context limited by the final reserve if ctx is already done,
+// so the last steps of the shutdown still get a chance to run. The caller must hold mu.
+func reserveContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Err() != nil && pkgFinalReserve > 0 {
+		return context.WithTimeout(detachContext(ctx), pkgFinalReserve)
+	}
+
+	return ctx, func() {}
+}
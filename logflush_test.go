@@ -0,0 +1,61 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// orderNotifier records the completion notification to the shared order.
+type orderNotifier struct {
+	order *[]string
+}
+
+func (n orderNotifier) ShutdownStarted(context.Context) error {
+	return nil
+}
+
+func (n orderNotifier) ShutdownCompleted(context.Context, *Report, error) error {
+	*n.order = append(*n.order, "notifier")
+	return nil
+}
+
+func TestRegisterLogFlusher(t *testing.T) {
+	resetPackage(&Lifo{})
+	pkgFinal = &Lifo{}
+
+	var order []string
+
+	SetNotifier(orderNotifier{order: &order})
+
+	defer func() {
+		SetNotifier(nil)
+		pkgLogFlushers = nil
+	}()
+
+	RegisterLogFlusher(func(ctx context.Context) error {
+		assert.NoError(t, ctx.Err())
+
+		order = append(order, "flush")
+
+		return nil
+	})
+	AppendFinal(Fn(func() error {
+		order = append(order, "final")
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	Append(Fn(func() error {
+		cancel() // The shutdown context is done before the flushers run
+		return nil
+	}))
+	Append(Fn(func() error {
+		order = append(order, "closer")
+		return nil
+	}))
+
+	_ = CloseContext(ctx)
+	assert.Equal(t, []string{"closer", "final", "notifier", "flush"}, order)
+}
@@ -0,0 +1,148 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// LoggerMode defines how the Logger of the signal helpers is closed if it is also registered as a closer.
+type LoggerMode int32
+
+const (
+	// LoggerCloseLast skips the logger in the close sequence and closes it after the log flushers,
+	// so the messages about the shutdown are not lost because the logger was closed first.
+	LoggerCloseLast LoggerMode = iota
+	// LoggerCloseInOrder closes the logger where it is registered, as any other closer.
+	LoggerCloseInOrder
+)
+
+var (
+	pkgLogger  Logger                   // The logger of the last signal helper, see useLogger
+	loggerMode atomic.Int32             // The current LoggerMode
+	deferred   atomic.Pointer[[]Closer] // The closers of the logger skipped by the running close sequence
+)
+
+// WithLoggerMode sets how the Logger passed to CloseOnSignal and the other signal helpers is closed
+// if it is also registered in the global closure. The default is LoggerCloseLast.
+// A logger is recognized if it is a pointer registered as is, or wrapped by Named, WithTimeout and the like.
+func WithLoggerMode(mode LoggerMode) Option {
+	return func(o *options) error {
+		if err := o.applies("WithLoggerMode", targetInit); err != nil {
+			return err
+		}
+
+		if mode < LoggerCloseLast || mode > LoggerCloseInOrder {
+			return fmt.Errorf("%w: unknown logger mode %d", ErrInvalidOption, mode)
+		}
+
+		o.loggerMode = mode
+
+		return nil
+	}
+}
+
+// useLogger records the logger of a signal helper, to be recognized among the closers.
+func useLogger(logger Logger) {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+	pkgLogger = logger
+}
+
+// deferLogger makes the close sequence skip the closers of the logger, if it is registered
+// in the global closure. It returns the logger to close last, nil if none. The caller must hold mu.
+func deferLogger() Closer {
+	logger, ok := pkgLogger.(Closer)
+	if !ok || LoggerMode(loggerMode.Load()) != LoggerCloseLast || reflect.ValueOf(logger).Kind() != reflect.Ptr {
+		return nil
+	}
+
+	found := findCloser(pkgClosure, logger)
+	if len(found) == 0 {
+		return nil
+	}
+
+	deferred.Store(&found)
+
+	return logger
+}
+
+// closeLogger closes the logger skipped by the close sequence, if any. The caller must hold mu.
+func closeLogger(ctx context.Context, logger Closer) error {
+	deferred.Store(nil)
+
+	if logger == nil {
+		return nil
+	}
+
+	ctx, cancel := reserveContext(ctx)
+	defer cancel()
+
+	return closeCtx(ctx, logger)
+}
+
+// isDeferred reports whether the closer is skipped by the running close sequence.
+func isDeferred(closer Closer) bool {
+	found := deferred.Load()
+	if found == nil {
+		return false
+	}
+
+	for _, c := range *found {
+		if sameCloser(c, closer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findCloser returns the closers registered in the closure, including the nested closures,
+// that are or wrap the target, e.g. Named("logger", target).
+func findCloser(closure Closer, target Closer) []Closer {
+	var (
+		found []Closer
+		visit func(l lister)
+	)
+
+	// resolve follows the wrappers of the closer until the target or a nested closure.
+	resolve := func(closer Closer) (bool, lister) {
+		for c := closer; ; {
+			if sameCloser(c, target) {
+				return true, nil
+			}
+
+			if l, ok := c.(lister); ok {
+				return false, l
+			}
+
+			var ok bool
+			if c, ok = unwrapCloser(c); !ok {
+				return false, nil
+			}
+		}
+	}
+
+	visit = func(l lister) {
+		for _, head := range l.list() {
+			switch match, nested := resolve(head); {
+			case match:
+				found = append(found, head) // The close sequence calls the outermost wrapper
+			case nested != nil:
+				visit(nested)
+			}
+		}
+	}
+
+	if _, root := resolve(closure); root != nil {
+		visit(root)
+	}
+
+	return found
+}
+
+// sameCloser reports whether both closers are the same pointer.
+func sameCloser(a, b Closer) bool {
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.ValueOf(a).Kind() == reflect.Ptr && a == b
+}
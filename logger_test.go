@@ -0,0 +1,69 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closableLogger records its messages and the order of its close among the other closers.
+type closableLogger struct {
+	messages []string
+	order    *[]string
+}
+
+func (l *closableLogger) Msgf(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func (l *closableLogger) Close() error {
+	*l.order = append(*l.order, "logger")
+	return nil
+}
+
+func TestLoggerCloseLast(t *testing.T) {
+	var order []string
+
+	logger := &closableLogger{order: &order}
+
+	nested := &Fifo{}
+	nested.Append(Named("logger", logger))
+
+	lifo := &Lifo{}
+	lifo.Append(&recordCloser{name: "db", order: &order})
+	lifo.Append(nested)
+	lifo.Append(&recordCloser{name: "http", order: &order})
+
+	resetPackage(lifo)
+	defer resetPackage(&Lifo{})
+
+	trigger := TriggerFunc(func(context.Context) (string, error) { return "test", nil })
+	require.NoError(t, CloseOnTrigger(context.Background(), logger, trigger))
+
+	assert.Equal(t, []string{"http", "db", "logger"}, order)
+	assert.Nil(t, deferred.Load(), "Expected the skipped closers to be reset")
+}
+
+func TestLoggerCloseInOrder(t *testing.T) {
+	require.NoError(t, Init(WithLoggerMode(LoggerCloseInOrder)))
+	defer func() { require.NoError(t, Init(WithLoggerMode(LoggerCloseLast))) }()
+
+	var order []string
+
+	logger := &closableLogger{order: &order}
+
+	lifo := &Lifo{}
+	lifo.Append(&recordCloser{name: "db", order: &order})
+	lifo.Append(logger)
+
+	resetPackage(lifo)
+	defer resetPackage(&Lifo{})
+
+	trigger := TriggerFunc(func(context.Context) (string, error) { return "test", nil })
+	require.NoError(t, CloseOnTrigger(context.Background(), logger, trigger))
+
+	assert.Equal(t, []string{"logger", "db"}, order)
+}
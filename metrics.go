@@ -0,0 +1,129 @@
+package shutdown
+
+import (
+	"context"
+	"expvar"
+	"time"
+)
+
+// Metrics receives the measurements of a close sequence. Implement it on top of the metrics library
+// of the application, e.g. with Prometheus counters and histograms, or use ExpvarMetrics.
+type Metrics interface {
+	CloserAppended(name string)                           // Called when a closer is appended
+	CloserClosed(name string, d time.Duration, err error) // Called when a closer is closed
+	ShutdownCompleted(d time.Duration, err error)         // Called when the close sequence completes
+}
+
+// metricsClosure reports the close sequence of the wrapped Closure to Metrics.
+type metricsClosure struct {
+	decorator // The wrapped closure
+
+	metrics Metrics // The receiver of the measurements
+}
+
+// WithMetrics wraps the closure so that every appended closer, the close duration and the error
+// of each closer, and the total duration of the close sequence are reported to the metrics.
+// Closers are reported by the name shown in Plan.
+func WithMetrics(closure Closure, metrics Metrics) Closure {
+	return &metricsClosure{decorator: decorator{closure}, metrics: metrics}
+}
+
+// Append reports the closer to the metrics and appends it to the wrapped closure.
+func (m *metricsClosure) Append(closer Closer) {
+	name := describe(closer, 0).Name
+
+	m.metrics.CloserAppended(name)
+	m.Closure.Append(&measuredCloser{name: name, closer: closer, metrics: m.metrics})
+}
+
+// CloseContext closes the wrapped closure and reports the total duration of the close sequence.
+func (m *metricsClosure) CloseContext(ctx context.Context) error {
+	start := time.Now()
+	err := m.Closure.CloseContext(ctx)
+	m.metrics.ShutdownCompleted(time.Since(start), err)
+
+	return err
+}
+
+// Close closes the wrapped closure without context support.
+func (m *metricsClosure) Close() error {
+	return m.CloseContext(context.Background())
+}
+
+// WithContext associates the metrics closure with the given context.
+func (m *metricsClosure) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, m)
+}
+
+// measuredCloser reports the close duration and the error of the wrapped closer.
+type measuredCloser struct {
+	name    string  // The name the closer is reported by
+	closer  Closer  // The wrapped closer
+	metrics Metrics // The receiver of the measurements
+}
+
+// Name returns the name of the wrapped closer.
+func (c *measuredCloser) Name() string {
+	return c.name
+}
+
+// Close closes the wrapped closer and reports the outcome.
+func (c *measuredCloser) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+// CloseContext closes the wrapped closer with the context and reports the outcome.
+func (c *measuredCloser) CloseContext(ctx context.Context) error {
+	start := time.Now()
+	err := closeCtx(ctx, c.closer)
+	c.metrics.CloserClosed(c.name, time.Since(start), err)
+
+	return err
+}
+
+// ExpvarMetrics publishes the measurements as an expvar map, served on /debug/vars by
+// StartDebugServer. The map holds the number of appended closers ("closers"), the number of
+// failed closers ("failures"), the total duration of the last close sequence in seconds
+// ("duration_seconds"), and the close duration of each closer in seconds ("closer_duration_seconds").
+type ExpvarMetrics struct {
+	closers  expvar.Int   // Number of appended closers
+	failures expvar.Int   // Number of closers that returned an error
+	duration expvar.Float // Total duration of the last close sequence, in seconds
+	closed   expvar.Map   // Close duration of each closer, in seconds
+}
+
+// NewExpvarMetrics creates ExpvarMetrics published under the given name.
+// Like expvar.Publish, it panics if the name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{}
+	m.closed.Init()
+
+	vars := expvar.NewMap(name)
+	vars.Set("closers", &m.closers)
+	vars.Set("failures", &m.failures)
+	vars.Set("duration_seconds", &m.duration)
+	vars.Set("closer_duration_seconds", &m.closed)
+
+	return m
+}
+
+// CloserAppended counts the appended closer.
+func (m *ExpvarMetrics) CloserAppended(string) {
+	m.closers.Add(1)
+}
+
+// CloserClosed records the close duration of the closer and counts it as failed if err is not nil.
+func (m *ExpvarMetrics) CloserClosed(name string, d time.Duration, err error) {
+	seconds := new(expvar.Float)
+	seconds.Set(d.Seconds())
+	m.closed.Set(name, seconds)
+
+	if err != nil {
+		m.failures.Add(1)
+	}
+}
+
+// ShutdownCompleted records the total duration of the close sequence.
+func (m *ExpvarMetrics) ShutdownCompleted(d time.Duration, _ error) {
+	m.duration.Set(d.Seconds())
+}
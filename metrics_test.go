@@ -0,0 +1,97 @@
+package shutdown
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordMetrics records the measurements reported to it.
+type recordMetrics struct {
+	mx       sync.Mutex
+	appended []string
+	closed   map[string]error
+	total    time.Duration
+	err      error
+}
+
+func (m *recordMetrics) CloserAppended(name string) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.appended = append(m.appended, name)
+}
+
+func (m *recordMetrics) CloserClosed(name string, _ time.Duration, err error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.closed[name] = err
+}
+
+func (m *recordMetrics) ShutdownCompleted(d time.Duration, err error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.total, m.err = d, err
+}
+
+func TestWithMetrics(t *testing.T) {
+	metrics := &recordMetrics{closed: make(map[string]error)}
+	closure := WithMetrics(&Fifo{}, metrics)
+
+	errFailed := errors.New("failed")
+
+	closure.Append(Named("database", Fn(func() error { return nil })))
+	closure.Append(Named("cache", Fn(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return errFailed
+	})))
+
+	err := closure.Close()
+	assert.ErrorIs(t, err, errFailed)
+
+	assert.Equal(t, []string{"database", "cache"}, metrics.appended)
+	assert.NoError(t, metrics.closed["database"])
+	assert.ErrorIs(t, metrics.closed["cache"], errFailed)
+	assert.GreaterOrEqual(t, metrics.total, 10*time.Millisecond)
+	assert.ErrorIs(t, metrics.err, errFailed)
+
+	steps := closure.(*metricsClosure).Closure.(*Fifo).Plan()
+	if assert.Len(t, steps, 2) {
+		assert.Equal(t, "database", steps[0].Name)
+		assert.Equal(t, "cache", steps[1].Name)
+	}
+
+	extracted, ok := ClosureFromContext(closure.WithContext(context.Background()))
+	assert.True(t, ok)
+	assert.Equal(t, closure, extracted)
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	closure := WithMetrics(&Lifo{}, NewExpvarMetrics("shutdown_test_metrics"))
+
+	closure.Append(Named("database", Fn(func() error { return nil })))
+	closure.Append(Named("cache", Fn(func() error { return errors.New("failed") })))
+	assert.Error(t, closure.Close())
+
+	var vars struct {
+		Closers  int64              `json:"closers"`
+		Failures int64              `json:"failures"`
+		Duration float64            `json:"duration_seconds"`
+		Closed   map[string]float64 `json:"closer_duration_seconds"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get("shutdown_test_metrics").String()), &vars))
+	assert.Equal(t, int64(2), vars.Closers)
+	assert.Equal(t, int64(1), vars.Failures)
+	assert.Greater(t, vars.Duration, float64(0))
+	assert.Contains(t, vars.Closed, "database")
+	assert.Contains(t, vars.Closed, "cache")
+
+	assert.Panics(t, func() { NewExpvarMetrics("shutdown_test_metrics") })
+}
@@ -0,0 +1,37 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+)
+
+// namedCloser is a closer carrying a human-readable name.
+type namedCloser struct {
+	name   string // The name of the closer
+	closer Closer // The wrapped closer
+}
+
+// Named returns a closer with a human-readable name: its errors are prefixed with the name,
+// and the name is used when describing the shutdown plan.
+func Named(name string, c Closer) Closer {
+	return &namedCloser{name: name, closer: c}
+}
+
+// Name returns the name of the closer.
+func (n *namedCloser) Name() string {
+	return n.name
+}
+
+// Close closes the wrapped closer, prefixing its error with the name.
+func (n *namedCloser) Close() error {
+	return n.CloseContext(context.Background())
+}
+
+// CloseContext closes the wrapped closer with the context, prefixing its error with the name.
+func (n *namedCloser) CloseContext(ctx context.Context) error {
+	if err := closeCtx(ctx, n.closer); err != nil {
+		return fmt.Errorf("%s: %w", n.name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package shutdown
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamed(t *testing.T) {
+	expectedErr := errors.New("close error")
+	closer := Named("db", Fn(func() error { return expectedErr }))
+
+	err := closer.Close()
+	assert.ErrorIs(t, err, expectedErr)
+	assert.EqualError(t, err, "db: close error")
+	assert.NoError(t, Named("cache", &mockCloser{}).Close())
+
+	lifo := &Lifo{}
+	lifo.Append(closer)
+	assert.Equal(t, []Step{{Name: "db"}}, lifo.Plan())
+}
@@ -0,0 +1,74 @@
+package shutdown
+
+import (
+	"context"
+	"time"
+)
+
+// DeadlineSource tells which deadline bounds a shutdown, see CloseOnTrigger.
+type DeadlineSource string
+
+// The sources of the shutdown deadline.
+const (
+	DeadlineConfigured DeadlineSource = "configured" // The default timeout set with SetDefaultCloseTimeout
+	DeadlineTrigger    DeadlineSource = "trigger"    // The deadline provided by the trigger
+)
+
+// deadlineSourceKey is a private struct used as a unique key for storing
+// and retrieving the deadline source in the context.
+type deadlineSourceKey struct{}
+
+// DeadlineSourceFromContext retrieves the source of the deadline of the close context set by CloseOnTrigger.
+// It is recorded in the Report of a Closure2 closed with that context.
+func DeadlineSourceFromContext(ctx context.Context) (DeadlineSource, bool) {
+	source, ok := ctx.Value(deadlineSourceKey{}).(DeadlineSource)
+	return source, ok
+}
+
+// DeadlineProvider is implemented by the triggers knowing when the process will be killed after firing,
+// e.g. from a spot interruption notice, a preStop hook or the systemd stop timeout.
+type DeadlineProvider interface {
+	Deadline() (time.Time, bool) // Returns the deadline of the last firing, if any
+}
+
+// deadlineTrigger is a trigger providing a deadline once fired.
+type deadlineTrigger struct {
+	Trigger
+	deadline func() (time.Time, bool) // Returns the deadline once the trigger has fired
+}
+
+// Deadline returns the deadline of the trigger.
+func (t deadlineTrigger) Deadline() (time.Time, bool) {
+	return t.deadline()
+}
+
+// TriggerWithDeadline returns a trigger providing the deadline computed by the given function once it fires,
+// e.g. the termination time of a spot interruption notice:
+//
+//	trigger := shutdown.TriggerWithDeadline(spotTrigger, func() (time.Time, bool) {
+//		return notice.Time, !notice.Time.IsZero()
+//	})
+func TriggerWithDeadline(trigger Trigger, deadline func() (time.Time, bool)) Trigger {
+	return deadlineTrigger{Trigger: trigger, deadline: deadline}
+}
+
+// negotiateDeadline returns the tighter of the configured deadline, starting now, and the deadline
+// provided by the trigger, along with its source. The zero time means no deadline.
+func negotiateDeadline(configured time.Duration, trigger Trigger) (time.Time, DeadlineSource) {
+	var (
+		deadline time.Time
+		source   DeadlineSource
+	)
+
+	if configured > 0 {
+		deadline, source = time.Now().Add(configured), DeadlineConfigured
+	}
+
+	if p, ok := trigger.(DeadlineProvider); ok {
+		if d, ok := p.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline, source = d, DeadlineTrigger
+		}
+	}
+
+	return deadline, source
+}
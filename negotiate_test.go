@@ -0,0 +1,78 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportingClosure keeps the report of the last close sequence.
+type reportingClosure struct {
+	Closure2
+	report *Report
+}
+
+func (r *reportingClosure) CloseContext(ctx context.Context) (*Report, error) {
+	report, err := r.Closure2.CloseContext(ctx)
+	r.report = report
+
+	return report, err
+}
+
+func TestNegotiateDeadline(t *testing.T) {
+	fired := TriggerFunc(func(context.Context) (string, error) { return "test", nil })
+	soon := time.Now().Add(time.Second)
+
+	deadline, source := negotiateDeadline(0, fired)
+	assert.True(t, deadline.IsZero())
+	assert.Empty(t, source)
+
+	deadline, source = negotiateDeadline(time.Minute, fired)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	assert.Equal(t, DeadlineConfigured, source)
+
+	provided := TriggerWithDeadline(fired, func() (time.Time, bool) { return soon, true })
+
+	deadline, source = negotiateDeadline(time.Minute, provided)
+	assert.Equal(t, soon, deadline)
+	assert.Equal(t, DeadlineTrigger, source)
+
+	deadline, source = negotiateDeadline(time.Millisecond, provided)
+	assert.WithinDuration(t, time.Now(), deadline, time.Second)
+	assert.Equal(t, DeadlineConfigured, source)
+
+	none := TriggerWithDeadline(fired, func() (time.Time, bool) { return time.Time{}, false })
+
+	deadline, source = negotiateDeadline(0, none)
+	assert.True(t, deadline.IsZero())
+	assert.Empty(t, source)
+}
+
+func TestCloseOnTrigger_Deadline(t *testing.T) {
+	closer := &ctxCloser{}
+	closure := Upgrade(&Lifo{})
+	closure.Append(closer)
+
+	reporting := &reportingClosure{Closure2: closure}
+	resetPackage(Downgrade(reporting))
+	defer resetPackage(&Lifo{})
+
+	deadline := time.Now().Add(time.Minute)
+	trigger := TriggerWithDeadline(
+		TriggerFunc(func(context.Context) (string, error) { return "spot interruption", nil }),
+		func() (time.Time, bool) { return deadline, true },
+	)
+
+	logger := &mockLogger{}
+	require.NoError(t, CloseOnTrigger(context.Background(), logger, trigger))
+
+	got, ok := closer.ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, deadline, got, pkgFinalReserve, "Expected the trigger deadline, minus the final reserve")
+	assert.Contains(t, getLastLoggedMessage(logger), "set by the trigger")
+	require.NotNil(t, reporting.report)
+	assert.Equal(t, DeadlineTrigger, reporting.report.Deadline)
+}
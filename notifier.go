@@ -0,0 +1,210 @@
+package shutdown
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Notification event names passed to the built-in notifiers.
+const (
+	EventStarted     = "started"      // Shutdown has started.
+	EventCompleted   = "completed"    // Shutdown has completed.
+	EventSLOViolated = "slo_violated" // Shutdown has violated the latency SLO, see WithSLO.
+)
+
+// DefaultNotifyTimeout limits the delivery of a single notification.
+const DefaultNotifyTimeout = 5 * time.Second
+
+// Notifier is notified when the package-level shutdown starts and completes.
+// It is intended for pinging external alerting/on-call tooling.
+//
+// The notifications get a context limited by DefaultNotifyTimeout; the completion notification
+// gets it even if the shutdown context is already done, so that a timed-out shutdown is still reported.
+// The delivery errors do not fail the shutdown, they are logged with the logger of the signal helpers,
+// or with the standard logger.
+type Notifier interface {
+	ShutdownStarted(ctx context.Context) error                              // Called before any closer runs
+	ShutdownCompleted(ctx context.Context, report *Report, err error) error // Called after all closers finished
+}
+
+var pkgNotifier Notifier // Notifier used by the package-level CloseContext, nil by default
+
+// SetNotifier sets the Notifier invoked by the package-level CloseContext.
+// Passing nil disables notifications.
+func SetNotifier(n Notifier) {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+	pkgNotifier = n
+}
+
+// notifyStarted notifies the notifier, if any, that the shutdown has started. The caller must hold mu.
+func notifyStarted(ctx context.Context) {
+	if pkgNotifier == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultNotifyTimeout)
+	defer cancel()
+
+	if err := pkgNotifier.ShutdownStarted(ctx); err != nil {
+		withRunID(ctx, notifyLogger()).Msgf("Shutdown notification failed: %s", err)
+	}
+}
+
+// notifyCompleted notifies the notifier, if any, about the report of the shutdown,
+// with a context detached from the possibly expired shutdown context. The caller must hold mu.
+func notifyCompleted(ctx context.Context, report *Report, err error) {
+	if pkgNotifier == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(detachContext(ctx), DefaultNotifyTimeout)
+	defer cancel()
+
+	if err := pkgNotifier.ShutdownCompleted(ctx, report, err); err != nil {
+		withRunID(ctx, notifyLogger()).Msgf("Shutdown notification failed: %s", err)
+	}
+}
+
+// notifyLogger returns the logger of the notification failures. The caller must hold mu.
+func notifyLogger() Logger {
+	if pkgLogger != nil {
+		return pkgLogger
+	}
+
+	return stdLogger{}
+}
+
+// notification is the payload sent by the built-in notifiers.
+type notification struct {
+	Event  string        `json:"event"`
+	RunID  string        `json:"run_id,omitempty"`
+	Error  string        `json:"error,omitempty"`
+	Time   time.Time     `json:"time"`
+	Report *reportRecord `json:"report,omitempty"`
+}
+
+func newNotification(ctx context.Context, event string, err error) notification {
+	n := notification{Event: event, Time: time.Now()}
+	n.RunID, _ = RunIDFromContext(ctx)
+
+	if err != nil {
+		n.Error = err.Error()
+	}
+
+	return n
+}
+
+// newCompletion returns the "completed" notification, including the report if not nil.
+func newCompletion(ctx context.Context, report *Report, err error) notification {
+	n := newNotification(ctx, EventCompleted, err)
+
+	if report != nil {
+		record := newReportRecord(report, err)
+		n.Report = &record
+	}
+
+	return n
+}
+
+// WebhookNotifier posts a JSON notification to the configured URL on every shutdown event.
+type WebhookNotifier struct {
+	URL    string       // The URL to post notifications to
+	Client *http.Client // The HTTP client to use, http.DefaultClient if nil
+}
+
+// ShutdownStarted posts the "started" event to the webhook.
+func (w *WebhookNotifier) ShutdownStarted(ctx context.Context) error {
+	return w.post(ctx, newNotification(ctx, EventStarted, nil))
+}
+
+// ShutdownCompleted posts the "completed" event, including the report and the shutdown error if any, to the webhook.
+func (w *WebhookNotifier) ShutdownCompleted(ctx context.Context, report *Report, err error) error {
+	return w.post(ctx, newCompletion(ctx, report, err))
+}
+
+// SLOViolated posts the "slo_violated" event, including the violation, to the webhook.
+func (w *WebhookNotifier) SLOViolated(ctx context.Context, v *SLOViolation) error {
+	return w.post(ctx, newNotification(ctx, EventSLOViolated, v))
+}
+
+func (w *WebhookNotifier) post(ctx context.Context, n notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("shutdown: cannot marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("shutdown: cannot create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("shutdown: webhook request failed: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("shutdown: webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CommandNotifier executes a command on every shutdown event.
+// The event name, run ID and error are passed to the command via the SHUTDOWN_EVENT,
+// SHUTDOWN_RUN_ID and SHUTDOWN_ERROR environment variables, and the duration and the summary
+// of the report of the "completed" event via SHUTDOWN_DURATION and SHUTDOWN_SUMMARY.
+type CommandNotifier struct {
+	Name string   // The command to execute
+	Args []string // The command arguments
+}
+
+// ShutdownStarted executes the command for the "started" event.
+func (c *CommandNotifier) ShutdownStarted(ctx context.Context) error {
+	return c.run(ctx, newNotification(ctx, EventStarted, nil))
+}
+
+// ShutdownCompleted executes the command for the "completed" event.
+func (c *CommandNotifier) ShutdownCompleted(ctx context.Context, report *Report, err error) error {
+	return c.run(ctx, newCompletion(ctx, report, err))
+}
+
+// SLOViolated executes the command for the "slo_violated" event.
+func (c *CommandNotifier) SLOViolated(ctx context.Context, v *SLOViolation) error {
+	return c.run(ctx, newNotification(ctx, EventSLOViolated, v))
+}
+
+func (c *CommandNotifier) run(ctx context.Context, n notification) error {
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...) //nolint:gosec // the command is configured by the application
+	cmd.Env = append(os.Environ(),
+		"SHUTDOWN_EVENT="+n.Event,
+		"SHUTDOWN_RUN_ID="+n.RunID,
+		"SHUTDOWN_ERROR="+n.Error,
+	)
+
+	if n.Report != nil {
+		cmd.Env = append(cmd.Env, "SHUTDOWN_DURATION="+n.Report.Duration, "SHUTDOWN_SUMMARY="+n.Report.Summary)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("shutdown: notifier command failed: %w: %s", err, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package shutdown
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordNotifier struct {
+	events []string
+	runIDs []string
+	report *Report
+	err    error
+}
+
+func (r *recordNotifier) ShutdownStarted(ctx context.Context) error {
+	runID, _ := RunIDFromContext(ctx)
+	r.events = append(r.events, EventStarted)
+	r.runIDs = append(r.runIDs, runID)
+	return nil
+}
+
+func (r *recordNotifier) ShutdownCompleted(ctx context.Context, report *Report, err error) error {
+	runID, _ := RunIDFromContext(ctx)
+	r.events = append(r.events, EventCompleted)
+	r.runIDs = append(r.runIDs, runID)
+	r.report = report
+	r.err = err
+	return nil
+}
+
+func TestSetNotifier(t *testing.T) {
+	defer SetNotifier(nil)
+
+	n := &recordNotifier{}
+	SetNotifier(n)
+	resetPackage(&Lifo{})
+	pkgFinal = &Lifo{}
+
+	expectedErr := errors.New("close error")
+	Append(Named("database", Fn(func() error { return nil })))
+	Append(Named("cache", Fn(func() error { return expectedErr })))
+
+	err := Close()
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, []string{EventStarted, EventCompleted}, n.events)
+	assert.ErrorIs(t, n.err, expectedErr)
+	assert.Len(t, n.runIDs, 2)
+	assert.NotEmpty(t, n.runIDs[0])
+	assert.Equal(t, n.runIDs[0], n.runIDs[1])
+
+	if assert.NotNil(t, n.report) {
+		assert.Equal(t, n.runIDs[0], n.report.RunID)
+
+		if assert.Len(t, n.report.Results, 2) {
+			assert.Equal(t, "cache", n.report.Results[0].Name)
+			assert.ErrorIs(t, n.report.Results[0].Err, expectedErr)
+			assert.Equal(t, "database", n.report.Results[1].Name)
+			assert.NoError(t, n.report.Results[1].Err)
+		}
+	}
+}
+
+// failingNotifier fails to deliver the notifications, recording whether the completion got a live context.
+type failingNotifier struct {
+	ctxErr error
+}
+
+func (f *failingNotifier) ShutdownStarted(context.Context) error {
+	return errors.New("unreachable")
+}
+
+func (f *failingNotifier) ShutdownCompleted(ctx context.Context, _ *Report, _ error) error {
+	f.ctxErr = ctx.Err()
+	return errors.New("unreachable")
+}
+
+func TestSetNotifier_TimedOut(t *testing.T) {
+	defer SetNotifier(nil)
+
+	n := &failingNotifier{}
+	SetNotifier(n)
+	resetPackage(&Lifo{})
+
+	logger := &mockLogger{}
+	pkgLogger = logger
+
+	Append(Fn(func() error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}))
+
+	// The shutdown times out, the completion is still delivered with a live context,
+	// and the delivery errors do not fail the shutdown.
+	err := CloseWithTimeout(10 * time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotContains(t, err.Error(), "unreachable")
+	assert.NoError(t, n.ctxErr)
+	assert.Regexp(t, `^\[run [0-9a-f]{32}\] Shutdown notification failed: unreachable`, getLastLoggedMessage(logger))
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var (
+		mx       sync.Mutex
+		received []notification
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n notification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&n))
+
+		mx.Lock()
+		received = append(received, n)
+		mx.Unlock()
+	}))
+	defer srv.Close()
+
+	ctx := RunIDToContext(context.Background(), "run-1")
+	n := &WebhookNotifier{URL: srv.URL}
+	assert.NoError(t, n.ShutdownStarted(ctx))
+	report := &Report{RunID: "run-1", Duration: time.Second}
+	assert.NoError(t, n.ShutdownCompleted(ctx, report, errors.New("close error")))
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, EventStarted, received[0].Event)
+		assert.Equal(t, EventCompleted, received[1].Event)
+		assert.Equal(t, "close error", received[1].Error)
+		assert.Equal(t, "run-1", received[1].RunID)
+
+		if assert.NotNil(t, received[1].Report) {
+			assert.Equal(t, "1s", received[1].Report.Duration)
+		}
+	}
+}
+
+func TestWebhookNotifier_BadStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	n := &WebhookNotifier{URL: srv.URL}
+	assert.Error(t, n.ShutdownStarted(context.Background()))
+}
+
+func TestCommandNotifier(t *testing.T) {
+	n := &CommandNotifier{Name: "sh", Args: []string{"-c",
+		`test "$SHUTDOWN_EVENT" = "completed" && test "$SHUTDOWN_ERROR" = "boom" && test "$SHUTDOWN_DURATION" = "1s"`}}
+	assert.NoError(t, n.ShutdownCompleted(context.Background(), &Report{Duration: time.Second}, errors.New("boom")))
+	assert.Error(t, n.ShutdownStarted(context.Background()))
+}
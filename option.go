@@ -0,0 +1,246 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidOption is returned (wrapped) by Init and the strategy constructors when an option is invalid,
+// or does not apply to them.
+var ErrInvalidOption = errors.New("shutdown: invalid option")
+
+// optionTarget is a set of the functions accepting options.
+type optionTarget uint8
+
+const (
+	targetInit     optionTarget = 1 << iota // Init
+	targetLifo                              // NewLifo
+	targetFifo                              // NewFifo
+	targetGroup                             // NewGroup
+	targetPriority                          // NewPriority
+	targetDag                               // NewDag
+
+	targetStrategies = targetLifo | targetFifo | targetGroup | targetPriority | targetDag // The strategy constructors
+	targetTraced     = targetLifo | targetFifo | targetGroup                              // The strategies supporting a Tracer
+)
+
+// String returns the name of the function accepting the options, e.g. "NewGroup".
+func (t optionTarget) String() string {
+	switch t {
+	case targetInit:
+		return "Init"
+	case targetLifo:
+		return "NewLifo"
+	case targetFifo:
+		return "NewFifo"
+	case targetGroup:
+		return "NewGroup"
+	case targetPriority:
+		return "NewPriority"
+	case targetDag:
+		return "NewDag"
+	default:
+		return fmt.Sprintf("optionTarget(%d)", uint8(t))
+	}
+}
+
+// options holds the configuration applied by the options.
+type options struct {
+	target          optionTarget // The function the options are applied by
+	closure         Closure
+	notifier        Notifier
+	timeout         time.Duration
+	finalReserve    time.Duration
+	quitMode        QuitMode
+	requireDeadline bool
+	concurrency     int
+	maxClosers      int
+	forceExitCode   int
+	loggerMode      LoggerMode
+	tracer          Tracer
+}
+
+// Option configures the package singleton, see Init, or a strategy built with a constructor such as NewGroup.
+// Each option documents what it applies to; an option passed to a function it does not apply to
+// makes the function fail with ErrInvalidOption, instead of being ignored.
+type Option func(*options) error
+
+// applies returns an ErrInvalidOption error if the named option does not apply to the current target.
+func (o *options) applies(name string, targets optionTarget) error {
+	if o.target&targets == 0 {
+		return fmt.Errorf("%w: %s does not apply to %s", ErrInvalidOption, name, o.target)
+	}
+
+	return nil
+}
+
+// WithClosure sets the strategy of the global closure, e.g. &Fifo{} or &Group{}.
+func WithClosure(c Closure) Option {
+	return func(o *options) error {
+		if err := o.applies("WithClosure", targetInit); err != nil {
+			return err
+		}
+
+		if c == nil {
+			return fmt.Errorf("%w: nil closure", ErrInvalidOption)
+		}
+
+		o.closure = c
+
+		return nil
+	}
+}
+
+// WithNotifier sets the Notifier of the shutdown start and completion.
+func WithNotifier(n Notifier) Option {
+	return func(o *options) error {
+		if err := o.applies("WithNotifier", targetInit); err != nil {
+			return err
+		}
+
+		o.notifier = n
+
+		return nil
+	}
+}
+
+// WithCloseTimeout sets the default timeout of the package-level Close,
+// or of the Close method of a strategy built with a constructor such as NewLifo or NewDag.
+func WithCloseTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if err := o.applies("WithCloseTimeout", targetInit|targetStrategies); err != nil {
+			return err
+		}
+
+		if d < 0 {
+			return fmt.Errorf("%w: negative close timeout %s", ErrInvalidOption, d)
+		}
+
+		o.timeout = d
+
+		return nil
+	}
+}
+
+// WithConcurrency limits the number of closers a Group built with NewGroup closes at the same time,
+// e.g. to avoid hammering a shared dependency; zero means no limit.
+func WithConcurrency(n int) Option {
+	return func(o *options) error {
+		if err := o.applies("WithConcurrency", targetGroup); err != nil {
+			return err
+		}
+
+		if n < 0 {
+			return fmt.Errorf("%w: negative concurrency %d", ErrInvalidOption, n)
+		}
+
+		o.concurrency = n
+
+		return nil
+	}
+}
+
+// WithFinalReserve sets the time slice reserved for the closers appended with AppendFinal.
+func WithFinalReserve(d time.Duration) Option {
+	return func(o *options) error {
+		if err := o.applies("WithFinalReserve", targetInit); err != nil {
+			return err
+		}
+
+		if d < 0 {
+			return fmt.Errorf("%w: negative final reserve %s", ErrInvalidOption, d)
+		}
+
+		o.finalReserve = d
+
+		return nil
+	}
+}
+
+// WithQuitMode sets how SIGQUIT is handled by the signal helpers.
+func WithQuitMode(mode QuitMode) Option {
+	return func(o *options) error {
+		if err := o.applies("WithQuitMode", targetInit); err != nil {
+			return err
+		}
+
+		if mode < QuitGraceful || mode > QuitDump {
+			return fmt.Errorf("%w: unknown quit mode %d", ErrInvalidOption, mode)
+		}
+
+		o.quitMode = mode
+
+		return nil
+	}
+}
+
+// newOptions applies the options to the zero configuration, for the given strategy constructor.
+func newOptions(target optionTarget, opts []Option) (options, error) {
+	o := options{target: target}
+
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return o, err
+		}
+	}
+
+	return o, nil
+}
+
+// closeWithin closes the closer within d; zero means no timeout.
+func closeWithin(c ContextCloser, d time.Duration) error {
+	if d <= 0 {
+		return c.CloseContext(context.Background())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return c.CloseContext(ctx)
+}
+
+// Init configures the package singleton in one call at program start,
+// instead of calling SetPackageClosure, SetNotifier and the other setters one by one.
+// The options are applied on top of the current configuration; if any option is invalid,
+// nothing is applied and the error is returned.
+func Init(opts ...Option) error {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+
+	o := options{
+		target:          targetInit,
+		closure:         pkgClosure,
+		notifier:        pkgNotifier,
+		timeout:         pkgTimeout,
+		finalReserve:    pkgFinalReserve,
+		quitMode:        QuitMode(quitMode.Load()),
+		requireDeadline: pkgRequireDeadline,
+		maxClosers:      pkgMaxClosers,
+		forceExitCode:   exitCode(),
+		loggerMode:      LoggerMode(loggerMode.Load()),
+	}
+
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return err
+		}
+	}
+
+	if d, ok := pkgClosure.(drainer); ok && pkgClosure != o.closure {
+		AppendAll(o.closure, d.drain()...) // Migrate the registered closers, as SetPackageClosure does
+	}
+
+	pkgClosure = o.closure
+	pkgNotifier = o.notifier
+	pkgTimeout = o.timeout
+	pkgFinalReserve = o.finalReserve
+	quitMode.Store(int32(o.quitMode))
+	pkgRequireDeadline = o.requireDeadline
+	pkgMaxClosers = o.maxClosers
+	forceExitCode.Store(int32(o.forceExitCode))
+	loggerMode.Store(int32(o.loggerMode))
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package shutdown
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	defer func() {
+		assert.NoError(t, Init(
+			WithClosure(&Lifo{}),
+			WithNotifier(nil),
+			WithCloseTimeout(0),
+			WithFinalReserve(DefaultFinalReserve),
+			WithQuitMode(QuitGraceful),
+		))
+	}()
+
+	fifo := &Fifo{}
+	notifier := &recordNotifier{}
+
+	err := Init(
+		WithClosure(fifo),
+		WithNotifier(notifier),
+		WithCloseTimeout(5*time.Second),
+		WithFinalReserve(time.Second),
+		WithQuitMode(QuitDump),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, fifo, pkgClosure)
+	assert.Equal(t, notifier, pkgNotifier)
+	assert.Equal(t, 5*time.Second, pkgTimeout)
+	assert.Equal(t, time.Second, pkgFinalReserve)
+	assert.Equal(t, QuitDump, QuitMode(quitMode.Load()))
+}
+
+func TestInit_Invalid(t *testing.T) {
+	lifo := &Lifo{}
+	resetPackage(lifo)
+
+	for name, opt := range map[string]Option{
+		"closure":       WithClosure(nil),
+		"close timeout": WithCloseTimeout(-time.Second),
+		"final reserve": WithFinalReserve(-time.Second),
+		"quit mode":     WithQuitMode(QuitMode(42)),
+		"max closers":   WithMaxClosers(-1),
+		"exit code":     WithForceExitCode(256),
+		"logger mode":   WithLoggerMode(LoggerMode(42)),
+		"concurrency":   WithConcurrency(2),
+		"tracer":        WithTracer(nil),
+	} {
+		err := Init(WithClosure(&Fifo{}), opt)
+		assert.ErrorIs(t, err, ErrInvalidOption, name)
+		assert.Equal(t, lifo, pkgClosure, "Expected nothing to be applied with an invalid %s", name)
+	}
+}
+
+func TestNewStrategies(t *testing.T) {
+	lifo, err := NewLifo(WithCloseTimeout(20 * time.Millisecond))
+	require.NoError(t, err)
+
+	fifo, err := NewFifo(WithCloseTimeout(20 * time.Millisecond))
+	require.NoError(t, err)
+
+	group, err := NewGroup(WithCloseTimeout(20 * time.Millisecond))
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	for name, closure := range map[string]Closure{"lifo": lifo, "fifo": fifo, "group": group} {
+		closure.Append(Fn(func() error {
+			<-release
+			return nil
+		}))
+
+		if name == "group" {
+			assert.NoError(t, closure.Close(), name) // Group does not report the context error
+		} else {
+			assert.ErrorIs(t, closure.Close(), context.DeadlineExceeded, name)
+		}
+	}
+
+	_, err = NewGroup(WithConcurrency(-1))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+}
+
+func TestNewStrategies_InapplicableOption(t *testing.T) {
+	_, err := NewFifo(WithConcurrency(2))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	assert.EqualError(t, err, "shutdown: invalid option: WithConcurrency does not apply to NewFifo")
+
+	_, err = NewDag(WithTracer(nil))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	_, err = NewPriority(WithMaxClosers(10))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	_, err = NewLifo(WithCloseTimeout(time.Second), WithTracer(nil))
+	assert.NoError(t, err)
+}
+
+func TestNewGroup_Concurrency(t *testing.T) {
+	group, err := NewGroup(WithConcurrency(2))
+	require.NoError(t, err)
+
+	var running, peak atomic.Int32
+
+	for i := 0; i < 6; i++ {
+		group.Append(Fn(func() error {
+			n := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			return nil
+		}))
+	}
+
+	assert.NoError(t, group.Close())
+	assert.Equal(t, int32(2), peak.Load())
+	assert.Equal(t, 6, group.Completed())
+}
@@ -0,0 +1,41 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// PacketConnCloser gracefully closes a packet-based (UDP, QUIC) server.
+// It stops reading new packets, waits for the in-flight handlers and then closes the connection.
+type PacketConnCloser struct {
+	conn     net.PacketConn // The connection to close
+	inFlight *InFlight      // Tracks the handlers of the received packets, may be nil
+}
+
+// PacketConn returns a closer for the given packet connection.
+// The read loop of the server is expected to return once ReadFrom fails,
+// and handlers are expected to be tracked via the given InFlight.
+func PacketConn(conn net.PacketConn, inFlight *InFlight) *PacketConnCloser {
+	return &PacketConnCloser{conn: conn, inFlight: inFlight}
+}
+
+// CloseContext stops reading new packets, waits for the in-flight handlers
+// until the context is done, and then closes the connection.
+func (p *PacketConnCloser) CloseContext(ctx context.Context) error {
+	// Unblock pending reads, so the read loop stops accepting new packets.
+	errs := p.conn.SetReadDeadline(time.Now())
+
+	if p.inFlight != nil {
+		errs = multierr.Append(errs, p.inFlight.Wait(ctx)) // Wait for the handlers to finish
+	}
+
+	return multierr.Append(errs, p.conn.Close())
+}
+
+// Close stops the server without a deadline for the in-flight handlers.
+func (p *PacketConnCloser) Close() error {
+	return p.CloseContext(context.Background())
+}
@@ -0,0 +1,60 @@
+package shutdown
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketConnCloser(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var (
+		inFlight = &InFlight{}
+		handled  int32
+		stopped  = make(chan struct{})
+	)
+
+	go func() {
+		defer close(stopped)
+
+		buf := make([]byte, 16)
+
+		for {
+			if _, _, err := conn.ReadFrom(buf); err != nil {
+				return
+			}
+
+			inFlight.Add()
+
+			go func() {
+				defer inFlight.Done()
+
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&handled, 1)
+			}()
+		}
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Write([]byte("ping"))
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	assert.NoError(t, PacketConn(conn, inFlight).Close())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&handled))
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the read loop to stop")
+	}
+}
@@ -0,0 +1,156 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"runtime/pprof"
+	"sync/atomic"
+)
+
+// PanicPolicy defines what happens when a closer panics during Close.
+type PanicPolicy int32
+
+const (
+	// PanicContinue recovers the panic, reports it as a *PanicError, carrying the stack trace,
+	// and continues with the remaining closers. It is the default, since a crash mid-shutdown
+	// leaves all the remaining resources unclosed.
+	PanicContinue PanicPolicy = iota
+	// PanicCrash lets the panic crash the process, as if the closer was called directly.
+	PanicCrash
+	// PanicAbort recovers the panic, reports it as a *PanicError and does not start the remaining closers.
+	PanicAbort
+	// PanicExit dumps the panic and the goroutines to os.Stderr and exits immediately with status 2.
+	// It suits storage engines, where closing anything else after a corrupted state is worse than not closing at all.
+	PanicExit
+)
+
+var (
+	panicPolicy atomic.Int32             // The global PanicPolicy
+	panicWriter io.Writer    = os.Stderr // Writer of the dump in PanicExit policy
+	panicExit                = exit      // Exits the process in PanicExit policy
+)
+
+// exit exits the process with the same status as an unrecovered panic.
+func exit() {
+	os.Exit(2)
+}
+
+// errAbort marks the panics recovered under the PanicAbort policy.
+var errAbort = errors.New("shutdown: close aborted")
+
+// PanicError is the error reported for a closer that panicked during Close.
+type PanicError struct {
+	Value any    // The value passed to panic
+	Stack []byte // The stack trace of the panicking goroutine
+	abort bool   // The remaining closers must not be started
+}
+
+// Error returns the message of the panic.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("shutdown: closer panicked: %v", e.Value)
+}
+
+// Is reports whether the panic aborted the close sequence, so that errors.Is(err, errAbort) holds.
+func (e *PanicError) Is(target error) bool {
+	return e.abort && target == errAbort
+}
+
+// crashPanic carries the panic of a closer under the PanicCrash policy through the enclosing closeWithPolicy
+// calls, which re-panic it instead of recovering it, e.g. under the global policy of the closure.
+type crashPanic struct {
+	value any // The value passed to panic
+}
+
+// Error returns the message of the panic, printed by the runtime when the process crashes.
+func (c *crashPanic) Error() string {
+	return fmt.Sprint(c.value)
+}
+
+// SetPanicPolicy sets the policy applied to the closers of Lifo, Fifo and Group panicking during Close.
+// The default is PanicContinue. WithPanicPolicy overrides the global policy for a single closer.
+func SetPanicPolicy(policy PanicPolicy) {
+	panicPolicy.Store(int32(policy))
+}
+
+// panicCloser applies its own PanicPolicy to the wrapped closer.
+type panicCloser struct {
+	closer Closer      // The wrapped closer
+	policy PanicPolicy // The policy of the closer
+}
+
+// WithPanicPolicy wraps the closer so that a panic during its Close is handled by the given policy,
+// regardless of the global one set by SetPanicPolicy.
+func WithPanicPolicy(policy PanicPolicy, c Closer) Closer {
+	return &panicCloser{closer: c, policy: policy}
+}
+
+// Close closes the wrapped closer applying the policy of the closer.
+func (p *panicCloser) Close() error {
+	return p.CloseContext(context.Background())
+}
+
+// CloseContext closes the wrapped closer with the context applying the policy of the closer.
+func (p *panicCloser) CloseContext(ctx context.Context) error {
+	return closeWithPolicy(ctx, p.closer, p.policy)
+}
+
+// policyKey is the context key of the PanicPolicy applied to the closer being closed.
+type policyKey struct{}
+
+// policyFromContext returns the PanicPolicy applied to the closer being closed,
+// or the global policy if the closer is not closed by closeWithPolicy.
+func policyFromContext(ctx context.Context) PanicPolicy {
+	if policy, ok := ctx.Value(policyKey{}).(PanicPolicy); ok {
+		return policy
+	}
+
+	return PanicPolicy(panicPolicy.Load())
+}
+
+// closeWithPolicy closes the closer, handling a panic according to the policy.
+// The policy is passed down with the context, so that the wrappers closing the closer
+// in another goroutine, such as the timed closers, apply it there.
+func closeWithPolicy(ctx context.Context, closer Closer, policy PanicPolicy) (err error) {
+	ctx = context.WithValue(ctx, policyKey{}, policy)
+
+	if policy == PanicCrash {
+		defer func() {
+			if value := recover(); value != nil {
+				if _, ok := value.(*crashPanic); !ok {
+					value = &crashPanic{value: value}
+				}
+
+				panic(value) // Mark the panic, so that it is not recovered by the enclosing policies
+			}
+		}()
+
+		return closeCtx(ctx, closer)
+	}
+
+	defer func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+
+		if crash, ok := value.(*crashPanic); ok {
+			panic(crash) // The closer panicked under the PanicCrash policy
+		}
+
+		perr := &PanicError{Value: value, Stack: debug.Stack(), abort: policy == PanicAbort}
+
+		if policy == PanicExit {
+			_, _ = fmt.Fprintf(panicWriter, "%s\n\n%s\n", perr, perr.Stack)
+			_ = pprof.Lookup("goroutine").WriteTo(panicWriter, 2)
+			panicExit()
+		}
+
+		err = perr
+	}()
+
+	return closeCtx(ctx, closer)
+}
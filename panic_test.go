@@ -0,0 +1,109 @@
+package shutdown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panicking() Closer {
+	return Fn(func() error {
+		panic("boom")
+	})
+}
+
+// flagCloser records whether it was closed.
+type flagCloser struct {
+	closed bool
+}
+
+func (f *flagCloser) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestPanicPolicy_Continue(t *testing.T) {
+	assert.Equal(t, PanicContinue, PanicPolicy(panicPolicy.Load()), "Expected the panics to be recovered by default")
+
+	for name, closure := range map[string]Closure{
+		"lifo": &Lifo{}, "fifo": &Fifo{}, "group": &Group{}, "priority": &Priority{}, "dag": &Dag{},
+	} {
+		last := &flagCloser{}
+		closure.Append(last)
+		closure.Append(panicking())
+
+		err := closure.Close()
+
+		var perr *PanicError
+		require.ErrorAs(t, err, &perr, name)
+		assert.Equal(t, "boom", perr.Value, name)
+		assert.NotEmpty(t, perr.Stack, name)
+		assert.True(t, last.closed, name)
+	}
+}
+
+func TestPanicPolicy_Abort(t *testing.T) {
+	SetPanicPolicy(PanicAbort)
+	defer SetPanicPolicy(PanicContinue)
+
+	lifo := &Lifo{}
+	last := &flagCloser{}
+	lifo.Append(last)
+	lifo.Append(panicking())
+
+	err := lifo.Close()
+	assert.ErrorAs(t, err, new(*PanicError))
+	assert.False(t, last.closed)
+
+	group := &Group{}
+	first := group.AppendAfter(panicking())
+	after := &flagCloser{}
+	group.AppendAfter(after, first)
+
+	err = group.Close()
+	assert.ErrorAs(t, err, new(*PanicError))
+	assert.False(t, after.closed)
+}
+
+func TestWithPanicPolicy(t *testing.T) {
+	fifo := &Fifo{}
+	last := &flagCloser{}
+	fifo.Append(WithPanicPolicy(PanicAbort, panicking()))
+	fifo.Append(last)
+
+	assert.ErrorAs(t, fifo.Close(), new(*PanicError))
+	assert.False(t, last.closed)
+}
+
+func TestWithPanicPolicy_Exit(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		exited bool
+	)
+
+	panicWriter, panicExit = &buf, func() { exited = true }
+	defer func() { panicWriter, panicExit = os.Stderr, exit }()
+
+	err := WithPanicPolicy(PanicExit, panicking()).Close()
+	assert.True(t, exited)
+	assert.True(t, errors.As(err, new(*PanicError)))
+	assert.Contains(t, buf.String(), "shutdown: closer panicked: boom")
+	assert.Contains(t, buf.String(), "goroutine")
+}
+
+func TestWithPanicPolicy_Crash(t *testing.T) {
+	closer := WithPanicPolicy(PanicCrash, panicking())
+
+	// The enclosing policies, such as the global PanicContinue of the closure, do not recover the panic.
+	assert.PanicsWithError(t, "boom", func() {
+		_ = closeWithPolicy(context.Background(), closer, PanicContinue)
+	})
+	assert.PanicsWithError(t, "boom", func() {
+		_ = closeWithPolicy(context.Background(), WithPanicPolicy(PanicAbort, closer), PanicContinue)
+	})
+}
@@ -0,0 +1,35 @@
+package shutdown
+
+import "context"
+
+// PartialCloser is implemented by the Closure2 implementations able to close everything
+// except the tagged essentials (admin endpoint, health server), supporting a "maintenance mode"
+// where the process stays alive for diagnostics after releasing its heavy resources.
+type PartialCloser interface {
+	CloseExcept(ctx context.Context, tags ...string) (*Report, error)
+}
+
+// CloseExceptOnTrigger waits for the trigger to fire or until the context is done, then closes the closure
+// except the closers having any of the given tags, e.g. releasing the heavy resources under memory pressure
+// while the admin endpoint stays alive for diagnostics:
+//
+//	go shutdown.CloseExceptOnTrigger(ctx, logger, shutdown.RSSTrigger(maxRSS, time.Second), closure, "essential")
+//
+// Like CloseOnTrigger, the closing is not bound to the cancellation of ctx.
+func CloseExceptOnTrigger(
+	ctx context.Context, logger Logger, trigger Trigger, closure PartialCloser, tags ...string,
+) (*Report, error) {
+	cause, err := trigger.Wait(ctx)
+
+	switch {
+	case err == nil:
+		logger.Msgf("Partial shutdown triggered by %s", cause)
+	case ctx.Err() != nil:
+		cause = ctx.Err().Error()
+		logger.Msgf("Partial shutdown triggered by %s", cause)
+	default:
+		return nil, err
+	}
+
+	return closure.CloseExcept(ReasonToContext(detachContext(ctx), cause), tags...)
+}
@@ -0,0 +1,78 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseExcept(t *testing.T) {
+	closure := Upgrade(&Lifo{})
+
+	closed := map[string]int{}
+	closer := func(name string) Closer {
+		return Fn(func() error {
+			closed[name]++
+			return nil
+		})
+	}
+
+	closure.Append(closer("admin"), WithName("admin"), WithTags("essential"))
+	closure.Append(closer("db"), WithName("db"))
+	closure.Append(closer("health"), WithName("health"), WithTags("essential"))
+
+	report, err := closure.(PartialCloser).CloseExcept(context.Background(), "essential")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"db": 1}, closed)
+	assert.Len(t, report.Results, 3)
+	assert.True(t, report.Results[0].Skipped)
+
+	report, err = closure.CloseContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"admin": 1, "db": 1, "health": 1}, closed)
+	assert.Len(t, report.Results, 2) // The db closer is not closed twice
+}
+
+func TestTagged_CloseExcept(t *testing.T) {
+	tagged := NewTagged(TagPolicy{Tag: "network", New: func() Closure { return &Group{} }})
+
+	closed := false
+	tagged.Append(&mockCloser{}, WithTags("network", "essential"))
+	tagged.Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	report, err := tagged.CloseExcept(context.Background(), "essential")
+	require.NoError(t, err)
+	assert.True(t, closed)
+
+	if assert.Len(t, report.Results, 2) {
+		assert.True(t, report.Results[0].Skipped)
+	}
+}
+
+func TestCloseExceptOnTrigger(t *testing.T) {
+	closure := Upgrade(&Lifo{})
+
+	closed := ""
+	closure.Append(&mockCloser{}, WithName("admin"), WithTags("essential"))
+	closure.Append(Fn(func() error {
+		closed = "db"
+		return nil
+	}), WithName("db"))
+
+	logger := &mockLogger{}
+	trigger := TriggerFunc(func(context.Context) (string, error) { return "rss bytes 2 exceeds 1", nil })
+
+	report, err := CloseExceptOnTrigger(context.Background(), logger, trigger, closure.(PartialCloser), "essential")
+	require.NoError(t, err)
+	assert.Equal(t, "db", closed)
+	assert.Equal(t, "Partial shutdown triggered by rss bytes 2 exceeds 1", getLastLoggedMessage(logger))
+
+	if assert.Len(t, report.Results, 2) {
+		assert.True(t, report.Results[1].Skipped) // The admin closer, closed last
+	}
+}
@@ -0,0 +1,54 @@
+// Package plan renders human-readable shutdown plans (order, phases, timeouts),
+// e.g. to be generated at build time for architecture reviews. Only the application knows
+// how to build its closure, so the generator is a small command of the application
+// registering the closers without starting anything:
+//
+//	// cmd/shutdownplan/main.go of the application
+//	func main() {
+//		closure := &shutdown.Lifo{}
+//		app.RegisterClosers(closure) // The function used by the application at startup
+//		fmt.Print(plan.Describe(closure))
+//	}
+//
+// and a directive next to it:
+//
+//	//go:generate go run ./cmd/shutdownplan > SHUTDOWN.md
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/partyzanex/shutdown"
+)
+
+// Describe returns the shutdown plan of the closure (or closer) as a numbered list.
+// Steps sharing a number are closed concurrently; composite closers are nested.
+func Describe(c any) string {
+	var steps []shutdown.Step
+
+	if p, ok := c.(shutdown.Planner); ok {
+		steps = p.Plan()
+	} else {
+		steps = []shutdown.Step{{Name: fmt.Sprintf("%T", c)}}
+	}
+
+	b := &strings.Builder{}
+	write(b, steps, "")
+
+	return b.String()
+}
+
+// write writes the steps to the builder, nesting the children with the given indent.
+func write(b *strings.Builder, steps []shutdown.Step, indent string) {
+	for _, step := range steps {
+		fmt.Fprintf(b, "%s%d. %s", indent, step.Phase+1, step.Name)
+
+		if step.Timeout > 0 {
+			fmt.Fprintf(b, " (timeout %s)", step.Timeout)
+		}
+
+		b.WriteString("\n")
+		write(b, step.Children, indent+"   ")
+	}
+}
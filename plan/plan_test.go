@@ -0,0 +1,47 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/partyzanex/shutdown"
+)
+
+type namedCloser struct{}
+
+func (namedCloser) Close() error { return nil }
+
+func TestDescribe(t *testing.T) {
+	closure := shutdown.Upgrade(&shutdown.Lifo{})
+	closure.Append(namedCloser{}, shutdown.WithName("db"), shutdown.WithTimeout(5*time.Second))
+	closure.Append(shutdown.ParallelPhase(namedCloser{}, namedCloser{}), shutdown.WithName("servers"))
+	closure.Append(namedCloser{})
+
+	expected := `1. plan.namedCloser
+2. servers
+   1. plan.namedCloser
+   1. plan.namedCloser
+3. db (timeout 5s)
+`
+	assert.Equal(t, expected, Describe(closure))
+}
+
+func TestDescribe_Group(t *testing.T) {
+	g := &shutdown.Group{}
+	first := g.AppendAfter(namedCloser{})
+	g.AppendAfter(shutdown.Sequence(namedCloser{}), first)
+	g.Append(namedCloser{})
+
+	expected := `1. plan.namedCloser
+1. plan.namedCloser
+2. *shutdown.Fifo
+   1. plan.namedCloser
+`
+	assert.Equal(t, expected, Describe(g))
+}
+
+func TestDescribe_NotPlanner(t *testing.T) {
+	assert.Equal(t, "1. plan.namedCloser\n", Describe(namedCloser{}))
+}
@@ -0,0 +1,112 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// TagCritical is the tag of the critical closers, which Preflight requires to be named.
+const TagCritical = "critical"
+
+// ErrPreflight is returned (wrapped) by Preflight when the shutdown is misconfigured.
+var ErrPreflight = errors.New("shutdown: preflight failed")
+
+// Preflight validates the plan of the global closure at startup, so that a misconfigured shutdown
+// fails fast at deploy time instead of during an incident:
+//   - the own timeouts of the closers, summed over the sequential phases, must fit in the grace period
+//     minus the final reserve; the grace period is the default close timeout, or else the time left
+//     until the deadline of ctx, and the check is skipped if there is neither;
+//   - the closers tagged TagCritical must be named.
+//
+// Dependency cycles need no check, since AppendAfter can only refer to already appended closers.
+// The global closure must be a Planner, such as Lifo, Fifo, Group or an upgraded closure.
+func Preflight(ctx context.Context) error {
+	mu.Lock()
+	closure, grace := pkgClosure, pkgTimeout
+
+	if grace <= 0 {
+		if deadline, ok := ctx.Deadline(); ok {
+			grace = time.Until(deadline)
+		}
+	}
+
+	reserve := finalReserve(time.Now().Add(grace))
+	mu.Unlock()
+
+	planner, ok := undecorate(closure).(Planner)
+	if !ok {
+		return fmt.Errorf("%w: %T cannot describe its plan", ErrPreflight, closure)
+	}
+
+	steps := planner.Plan()
+
+	var errs error
+
+	if grace > 0 {
+		if total := budget(steps); total > grace-reserve {
+			errs = multierr.Append(errs, fmt.Errorf("%w: the closer timeouts sum to %s, exceeding the grace period of %s minus the final reserve of %s",
+				ErrPreflight, total, grace, reserve))
+		}
+	}
+
+	for _, name := range unnamedCritical(steps) {
+		errs = multierr.Append(errs, fmt.Errorf("%w: critical closer %s is not named", ErrPreflight, name))
+	}
+
+	return errs
+}
+
+// budget returns the time needed by the steps in the worst case: the phases are sequential,
+// and the steps of a phase are concurrent. A step without its own timeout is bound by its children.
+func budget(steps []Step) time.Duration {
+	phases := make(map[int]time.Duration)
+
+	for _, step := range steps {
+		d := step.Timeout
+		if d <= 0 {
+			d = budget(step.Children)
+		}
+
+		if d > phases[step.Phase] {
+			phases[step.Phase] = d
+		}
+	}
+
+	var total time.Duration
+
+	for _, d := range phases {
+		total += d
+	}
+
+	return total
+}
+
+// unnamedCritical returns the type names of the unnamed steps tagged TagCritical, including the children.
+func unnamedCritical(steps []Step) []string {
+	var names []string
+
+	for _, step := range steps {
+		if step.Unnamed && hasTag(step.Tags, TagCritical) {
+			names = append(names, step.Name)
+		}
+
+		names = append(names, unnamedCritical(step.Children)...)
+	}
+
+	return names
+}
+
+// hasTag reports whether the tags contain the given tag.
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
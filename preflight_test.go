@@ -0,0 +1,51 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreflight(t *testing.T) {
+	closure := Upgrade(&Lifo{})
+	closure.Append(&mockCloser{}, WithName("db"), WithTimeout(5*time.Second), WithTags(TagCritical))
+	closure.Append(ParallelPhase(Named("http", &mockCloser{}), &mockCloser{}), WithTimeout(10*time.Second))
+
+	resetPackage(Downgrade(closure))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	assert.NoError(t, Preflight(ctx))
+	assert.NoError(t, Preflight(context.Background()), "no grace period, the budget is not checked")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	err := Preflight(ctx) // 15s of timeouts do not fit in 15s minus the final reserve
+	assert.ErrorIs(t, err, ErrPreflight)
+	assert.Contains(t, err.Error(), "the closer timeouts sum to 15s")
+}
+
+func TestPreflight_UnnamedCritical(t *testing.T) {
+	closure := Upgrade(&Group{})
+	closure.Append(&mockCloser{}, WithTags(TagCritical))
+	closure.Append(&mockCloser{}, WithTags("cache"))
+
+	resetPackage(Downgrade(closure))
+
+	err := Preflight(context.Background())
+	assert.ErrorIs(t, err, ErrPreflight)
+	assert.EqualError(t, err, "shutdown: preflight failed: critical closer *shutdown.mockCloser is not named")
+}
+
+// plainClosure hides the optional capabilities of the wrapped closure, such as Plan.
+type plainClosure struct{ Closure }
+
+func TestPreflight_NotPlanner(t *testing.T) {
+	resetPackage(WithTrace(plainClosure{&Lifo{}}))
+
+	assert.ErrorIs(t, Preflight(context.Background()), ErrPreflight)
+}
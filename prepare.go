@@ -0,0 +1,121 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// Preparer is implemented by closers that can do a part of their work before being closed,
+// e.g. start flushing buffers or stop accepting new jobs.
+type Preparer interface {
+	PrepareClose(ctx context.Context) error // Prepares the closer, ctx is done when the drain delay elapses
+}
+
+// prepareClosure runs the preparers of the wrapped Closure while the drain delay elapses.
+type prepareClosure struct {
+	decorator               // The wrapped closure
+	delay     time.Duration // The drain delay before closing
+	mx        sync.Mutex    // Mutex to protect the preparers
+	preparers []Preparer    // The appended closers implementing Preparer
+}
+
+// WithDrainDelay wraps the closure so that its close sequence starts after the delay,
+// giving load balancers the time to stop routing traffic to the instance.
+// The appended closers implementing Preparer are prepared concurrently while the delay elapses,
+// which shortens the critical path of the actual close phase.
+// The preparation is time-boxed: the context passed to PrepareClose is done when the delay elapses,
+// and the errors of preparers that have not returned by then are discarded.
+func WithDrainDelay(closure Closure, delay time.Duration) Closure {
+	return &prepareClosure{decorator: decorator{closure}, delay: delay}
+}
+
+// Append adds the closer to the wrapped closure and remembers it if it implements Preparer,
+// itself or through wrappers such as Named, WithTimeout or a Closure2 entry.
+func (p *prepareClosure) Append(closer Closer) {
+	if preparer := findPreparer(closer); preparer != nil {
+		p.mx.Lock()
+		p.preparers = append(p.preparers, preparer)
+		p.mx.Unlock()
+	}
+
+	p.Closure.Append(closer)
+}
+
+// findPreparer returns the Preparer wrapped by the closer, nil if none.
+// The closers of a nested closure are not prepared.
+func findPreparer(closer Closer) Preparer {
+	for {
+		if preparer, ok := closer.(Preparer); ok {
+			return preparer
+		}
+
+		if _, ok := closer.(lister); ok {
+			return nil
+		}
+
+		var ok bool
+		if closer, ok = unwrapCloser(closer); !ok {
+			return nil
+		}
+	}
+}
+
+// drain removes and returns the closers of the wrapped closure, forgetting the preparers,
+// so that SetPackageClosure migrates them to the new closure.
+func (p *prepareClosure) drain() []Closer {
+	p.mx.Lock()
+	p.preparers = nil
+	p.mx.Unlock()
+
+	return p.decorator.drain()
+}
+
+// CloseContext prepares the closers during the drain delay and then closes the wrapped closure.
+// The preparation errors are combined with the close errors.
+func (p *prepareClosure) CloseContext(ctx context.Context) error {
+	p.mx.Lock()
+	preparers := p.preparers
+	p.mx.Unlock()
+
+	prepareCtx, cancel := context.WithTimeout(ctx, p.delay)
+	defer cancel()
+
+	var (
+		errMx sync.Mutex
+		errs  error
+	)
+
+	for _, preparer := range preparers {
+		go func(preparer Preparer) {
+			err := preparer.PrepareClose(prepareCtx)
+
+			errMx.Lock()
+			defer errMx.Unlock()
+
+			if prepareCtx.Err() == nil { // Discard the errors reported after the delay
+				errs = multierr.Append(errs, err)
+			}
+		}(preparer)
+	}
+
+	<-prepareCtx.Done() // Wait for the drain delay, interrupted if ctx is done
+
+	errMx.Lock()
+	err := errs
+	errMx.Unlock()
+
+	return multierr.Append(err, p.Closure.CloseContext(ctx))
+}
+
+// Close prepares the closers during the drain delay and then closes the wrapped closure without context support.
+func (p *prepareClosure) Close() error {
+	return p.CloseContext(context.Background())
+}
+
+// WithContext associates the drain delay closure with the given context.
+func (p *prepareClosure) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, p)
+}
@@ -0,0 +1,106 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// preparingCloser records whether it was prepared before being closed.
+type preparingCloser struct {
+	prepared atomic.Bool
+	err      error
+	block    bool
+}
+
+func (p *preparingCloser) PrepareClose(ctx context.Context) error {
+	if p.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	p.prepared.Store(true)
+
+	return p.err
+}
+
+func (p *preparingCloser) Close() error {
+	return nil
+}
+
+func TestWithDrainDelay(t *testing.T) {
+	closure := WithDrainDelay(&Lifo{}, 20*time.Millisecond)
+
+	preparer := &preparingCloser{}
+	closure.Append(preparer)
+
+	var preparedBeforeClose bool
+
+	closure.Append(Fn(func() error {
+		preparedBeforeClose = preparer.prepared.Load()
+		return nil
+	}))
+
+	start := time.Now()
+	assert.NoError(t, closure.Close())
+	assert.True(t, preparedBeforeClose)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestWithDrainDelay_Errors(t *testing.T) {
+	closure := WithDrainDelay(&Lifo{}, 20*time.Millisecond)
+
+	errPrepare := errors.New("prepare failed")
+	closure.Append(&preparingCloser{err: errPrepare})
+	closure.Append(&preparingCloser{block: true}) // Its late error is discarded
+
+	err := closure.Close()
+	assert.ErrorIs(t, err, errPrepare)
+	assert.NotErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithDrainDelay_Context(t *testing.T) {
+	closure := WithDrainDelay(&Lifo{}, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.NoError(t, closure.CloseContext(ctx))
+	assert.Less(t, time.Since(start), time.Second)
+
+	extracted, ok := ClosureFromContext(closure.WithContext(context.Background()))
+	assert.True(t, ok)
+	assert.Equal(t, closure, extracted)
+}
+
+func TestWithDrainDelay_Wrapped(t *testing.T) {
+	closure := WithDrainDelay(&Lifo{}, 10*time.Millisecond)
+
+	named, timed := &preparingCloser{}, &preparingCloser{}
+	closure.Append(Named("named", named))
+	closure.Append(&timedCloser{closer: timed, timeout: time.Second})
+
+	assert.NoError(t, closure.Close())
+	assert.True(t, named.prepared.Load())
+	assert.True(t, timed.prepared.Load())
+}
+
+func TestWithDrainDelay_Migration(t *testing.T) {
+	resetPackage(WithDrainDelay(&Lifo{}, 10*time.Millisecond))
+
+	closed := false
+	Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	SetPackageClosure(&Fifo{})
+	require.NoError(t, Close())
+	assert.True(t, closed)
+}
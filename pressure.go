@@ -0,0 +1,78 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/metrics"
+	"time"
+)
+
+// heapObjectsMetric is the runtime metric of the memory occupied by the live and unswept heap objects,
+// the HeapAlloc of runtime.MemStats, read without stopping the world.
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// ThresholdTrigger returns a trigger that fires once the measured value exceeds the threshold.
+// The value is measured every interval, DefaultPollInterval if the interval is not positive;
+// measurement errors stop the trigger.
+func ThresholdTrigger(name string, measure func() (uint64, error), threshold uint64, interval time.Duration) Trigger {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			value, err := measure()
+			if err != nil {
+				return "", err
+			}
+
+			if value > threshold {
+				return fmt.Sprintf("%s %d exceeds %d", name, value, threshold), nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// HeapTrigger returns a trigger that fires once the allocated heap exceeds maxBytes,
+// letting the process bow out cleanly before the OOM killer does it rudely.
+// The heap is measured with runtime/metrics, which, unlike runtime.ReadMemStats, does not stop the world.
+func HeapTrigger(maxBytes uint64, interval time.Duration) Trigger {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+
+	return ThresholdTrigger("heap bytes", func() (uint64, error) {
+		metrics.Read(sample)
+
+		if sample[0].Value.Kind() != metrics.KindUint64 {
+			return 0, errors.New("shutdown: metric " + heapObjectsMetric + " is not supported")
+		}
+
+		return sample[0].Value.Uint64(), nil
+	}, maxBytes, interval)
+}
+
+// RSSTrigger returns a trigger that fires once the resident set size of the process exceeds maxBytes.
+// Unlike the heap, the RSS includes the stacks, the runtime overhead and the memory not yet returned
+// to the OS, which is what the OOM killer looks at. It is supported on Linux only, where it is read
+// from /proc/self/statm; elsewhere the trigger returns an error.
+func RSSTrigger(maxBytes uint64, interval time.Duration) Trigger {
+	return ThresholdTrigger("rss bytes", residentMemory, maxBytes, interval)
+}
+
+// FDTrigger returns a trigger that fires once the number of open file descriptors exceeds maxFDs.
+// It is supported on Linux only; elsewhere the trigger returns an error.
+func FDTrigger(maxFDs int, interval time.Duration) Trigger {
+	return ThresholdTrigger("open files", func() (uint64, error) {
+		files, err := openFiles()
+		return uint64(len(files)), err
+	}, uint64(maxFDs), interval)
+}
@@ -0,0 +1,65 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholdTrigger(t *testing.T) {
+	value := uint64(0)
+	measure := func() (uint64, error) {
+		value++
+		return value, nil
+	}
+
+	cause, err := ThresholdTrigger("value", measure, 3, time.Millisecond).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "value 4 exceeds 3", cause)
+
+	expectedErr := errors.New("measure error")
+	_, err = ThresholdTrigger("value", func() (uint64, error) { return 0, expectedErr }, 3, time.Millisecond).
+		Wait(context.Background())
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestHeapTrigger(t *testing.T) {
+	cause, err := HeapTrigger(1, time.Millisecond).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, cause, "heap bytes")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = HeapTrigger(1<<62, time.Millisecond).Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRSSTrigger(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the resident memory is read on Linux only")
+	}
+
+	rss, err := residentMemory()
+	require.NoError(t, err)
+	assert.NotZero(t, rss)
+
+	cause, err := RSSTrigger(1, 0).Wait(context.Background()) // The default interval
+	require.NoError(t, err)
+	assert.Contains(t, cause, "rss bytes")
+}
+
+func TestFDTrigger(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("open files are listed on Linux only")
+	}
+
+	cause, err := FDTrigger(1, time.Millisecond).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, cause, "open files")
+}
@@ -0,0 +1,151 @@
+package shutdown
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// Priority represents a collection of resources closed in descending priority order,
+// the closers of equal priority being closed concurrently. Unlike Lifo and Fifo, the order
+// does not depend on the order of registration, e.g. the listeners (priority 10) are always
+// stopped before the database pools (priority 0).
+type Priority struct {
+	progress
+
+	closers []prioritized // The list of resources to close, in the order of appending.
+	mx      sync.Mutex    // Mutex for thread safety.
+	timeout time.Duration // Timeout of Close, zero means no timeout.
+}
+
+// prioritized is a closer appended with its priority.
+type prioritized struct {
+	closer   Closer // The closer
+	priority int    // The priority, higher is closed first
+}
+
+// NewPriority returns a Priority configured by the options; WithCloseTimeout sets the timeout of Close.
+// The zero Priority is ready to use without options.
+func NewPriority(opts ...Option) (*Priority, error) {
+	o, err := newOptions(targetPriority, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Priority{timeout: o.timeout}, nil
+}
+
+// Append adds a new closer with the priority of its entry if it is appended through Upgrade
+// with WithPriority, zero otherwise.
+func (p *Priority) Append(closer Closer) {
+	priority := 0
+	if e, ok := closer.(*entryCloser); ok {
+		priority = e.entry.Priority
+	}
+
+	p.AppendWithPriority(closer, priority)
+}
+
+// AppendWithPriority adds a new closer with the given priority; higher priorities are closed first.
+func (p *Priority) AppendWithPriority(closer Closer, priority int) {
+	p.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer p.mx.Unlock() // Release the lock after the function finishes.
+	p.closers = append(p.closers, prioritized{closer: closer, priority: priority})
+	p.added()
+}
+
+// levels returns the closers grouped by priority, the highest first. The caller must hold mx.
+func (p *Priority) levels() [][]Closer {
+	sorted := append([]prioritized(nil), p.closers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].priority > sorted[j].priority
+	})
+
+	var levels [][]Closer
+
+	for i, c := range sorted {
+		if i == 0 || c.priority != sorted[i-1].priority {
+			levels = append(levels, nil)
+		}
+
+		levels[len(levels)-1] = append(levels[len(levels)-1], c.closer)
+	}
+
+	return levels
+}
+
+// CloseContext closes the resources level by level, the highest priority first,
+// the closers of a level concurrently. The next level starts once the whole level is closed.
+func (p *Priority) CloseContext(ctx context.Context) error {
+	p.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer p.mx.Unlock() // Release the lock after the function finishes.
+
+	var (
+		errs error      // This will store the accumulated errors.
+		mx   sync.Mutex // Local mutex for the errors, the closers of a level run concurrently.
+	)
+
+	p.start(len(p.closers))
+
+	for _, level := range p.levels() {
+		var (
+			wg      sync.WaitGroup
+			aborted atomic.Bool
+			next    = make(chan struct{}) // Channel to signal completion of the level.
+		)
+
+		wg.Add(len(level))
+
+		for _, closer := range level {
+			go func(closer Closer) {
+				defer wg.Done()
+
+				var err error
+
+				if callClose(ctx, closer, &err) {
+					aborted.Store(true)
+				}
+
+				mx.Lock()
+				errs = multierr.Append(errs, err)
+				mx.Unlock()
+
+				p.done()
+			}(closer)
+		}
+
+		go func() {
+			wg.Wait()
+			close(next)
+		}()
+
+		select {
+		case <-ctx.Done(): // If the context is cancelled or times out.
+			mx.Lock()
+			defer mx.Unlock()
+
+			return multierr.Append(errs, ctx.Err()) // Return the accumulated errors and the context error.
+		case <-next: // Move to the next level after the current one finishes.
+			if aborted.Load() {
+				return errs // A closer panicked under the PanicAbort policy.
+			}
+		}
+	}
+
+	return errs // Return the accumulated errors.
+}
+
+// Close attempts to close all resources in priority order without context support,
+// within the timeout set by NewPriority, if any.
+func (p *Priority) Close() error {
+	return closeWithin(p, p.timeout)
+}
+
+// WithContext embeds the Priority instance into the given context.
+func (p *Priority) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, p)
+}
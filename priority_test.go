@@ -0,0 +1,113 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriority(t *testing.T) {
+	var (
+		mx    sync.Mutex
+		order []string
+	)
+
+	record := func(name string) Closer {
+		return Fn(func() error {
+			mx.Lock()
+			defer mx.Unlock()
+
+			order = append(order, name)
+
+			return nil
+		})
+	}
+
+	p := &Priority{}
+	p.Append(record("db"))
+	p.AppendWithPriority(record("grpc"), 10)
+	p.AppendWithPriority(record("cache"), 5)
+	p.AppendWithPriority(record("http"), 10)
+
+	require.NoError(t, p.Close())
+	require.Len(t, order, 4)
+	assert.ElementsMatch(t, []string{"grpc", "http"}, order[:2])
+	assert.Equal(t, []string{"cache", "db"}, order[2:])
+	assert.Equal(t, 4, p.Completed())
+}
+
+func TestPriority_Concurrent(t *testing.T) {
+	p := &Priority{}
+	started := make(chan struct{})
+
+	// Both closers of the level must run at the same time, or the first one never returns.
+	p.AppendWithPriority(Fn(func() error {
+		<-started
+		return nil
+	}), 1)
+	p.AppendWithPriority(Fn(func() error {
+		close(started)
+		return nil
+	}), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, p.CloseContext(ctx))
+}
+
+func TestPriority_Errors(t *testing.T) {
+	errHigh, errLow := errors.New("high"), errors.New("low")
+
+	p := &Priority{}
+	p.AppendWithPriority(Fn(func() error { return errLow }), 1)
+	p.AppendWithPriority(Fn(func() error { return errHigh }), 2)
+
+	err := p.Close()
+	assert.ErrorIs(t, err, errHigh)
+	assert.ErrorIs(t, err, errLow)
+}
+
+func TestPriority_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	closed := false
+
+	p, err := NewPriority(WithCloseTimeout(10 * time.Millisecond))
+	require.NoError(t, err)
+
+	p.AppendWithPriority(Fn(func() error {
+		<-release
+		return nil
+	}), 1)
+	p.Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	assert.ErrorIs(t, p.Close(), context.DeadlineExceeded)
+	assert.False(t, closed, "Expected the lower priority not to be closed")
+}
+
+func TestPriority_Upgrade(t *testing.T) {
+	var order []string
+
+	p := &Priority{}
+	c := Upgrade(p)
+	c.Append(&recordCloser{name: "db", order: &order})
+	c.Append(&recordCloser{name: "http", order: &order}, WithPriority(1))
+
+	_, err := c.CloseContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http", "db"}, order)
+	assert.Equal(t, []Step{
+		{Name: "*shutdown.recordCloser", Unnamed: true},
+		{Name: "*shutdown.recordCloser", Phase: 1, Unnamed: true},
+	}, p.Plan())
+}
@@ -0,0 +1,80 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// profileClosure records a CPU profile and a heap snapshot of the close sequence of the wrapped Closure.
+type profileClosure struct {
+	decorator        // The wrapped closure
+	dir       string // Directory for the profiles
+}
+
+// WithProfile wraps the closure so that a CPU profile is recorded for the duration
+// of its close sequence, and a heap snapshot is taken after it. The profiles are written
+// to dir as shutdown-<timestamp>.cpu.pprof and shutdown-<timestamp>.heap.pprof.
+// Failures to profile are appended to the close error, they never prevent the closing.
+func WithProfile(closure Closure, dir string) Closure {
+	return &profileClosure{decorator: decorator{closure}, dir: dir}
+}
+
+// CloseContext closes the wrapped closure while recording the profiles.
+func (p *profileClosure) CloseContext(ctx context.Context) error {
+	prefix := filepath.Join(p.dir, "shutdown-"+time.Now().Format("20060102T150405.000"))
+
+	stop, errs := startCPUProfile(prefix + ".cpu.pprof")
+	errs = multierr.Append(errs, p.Closure.CloseContext(ctx))
+	errs = multierr.Append(errs, stop())
+
+	return multierr.Append(errs, writeHeapProfile(prefix+".heap.pprof"))
+}
+
+// Close closes the wrapped closure without context support while recording the profiles.
+func (p *profileClosure) Close() error {
+	return p.CloseContext(context.Background())
+}
+
+// WithContext associates the profile closure with the given context.
+func (p *profileClosure) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, p)
+}
+
+// startCPUProfile starts the CPU profiling to the file and returns the function stopping it.
+func startCPUProfile(path string) (func() error, error) {
+	noop := func() error { return nil }
+
+	f, err := os.Create(path)
+	if err != nil {
+		return noop, fmt.Errorf("shutdown: cannot create CPU profile: %w", err)
+	}
+
+	if err = pprof.StartCPUProfile(f); err != nil {
+		return noop, multierr.Append(fmt.Errorf("shutdown: cannot start CPU profile: %w", err), f.Close())
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		return f.Close()
+	}, nil
+}
+
+// writeHeapProfile writes a heap snapshot to the file.
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("shutdown: cannot create heap profile: %w", err)
+	}
+
+	if err = pprof.WriteHeapProfile(f); err != nil {
+		return multierr.Append(fmt.Errorf("shutdown: cannot write heap profile: %w", err), f.Close())
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,34 @@
+package shutdown
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithProfile(t *testing.T) {
+	dir := t.TempDir()
+	closure := WithProfile(&Lifo{}, dir)
+	closure.Append(&mockCloser{})
+
+	require.NoError(t, closure.Close())
+
+	cpu, _ := filepath.Glob(filepath.Join(dir, "shutdown-*.cpu.pprof"))
+	heap, _ := filepath.Glob(filepath.Join(dir, "shutdown-*.heap.pprof"))
+	assert.Len(t, cpu, 1)
+	assert.Len(t, heap, 1)
+}
+
+func TestWithProfile_MissingDir(t *testing.T) {
+	closed := false
+	closure := WithProfile(&Lifo{}, filepath.Join(t.TempDir(), "missing"))
+	closure.Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	assert.Error(t, closure.Close())
+	assert.True(t, closed)
+}
@@ -0,0 +1,60 @@
+package shutdown
+
+import "sync/atomic"
+
+// progress tracks the number of pending and completed closers of a closure.
+// It is embedded into the closure implementations to expose Pending, Completed and the churn counters.
+type progress struct {
+	pending   atomic.Int64 // Number of closers that are not closed yet
+	completed atomic.Int64 // Number of closers closed by the current (or last) close sequence
+	appended  atomic.Int64 // Number of closers appended over the lifetime of the closure
+	removed   atomic.Int64 // Number of closers removed over the lifetime of the closure
+}
+
+// Pending returns the number of closers that are not closed yet.
+// It is safe to call concurrently with CloseContext, e.g. from health or admin endpoints.
+func (p *progress) Pending() int {
+	return int(p.pending.Load())
+}
+
+// Completed returns the number of closers closed by the current (or last) close sequence.
+// It is safe to call concurrently with CloseContext, e.g. from health or admin endpoints.
+func (p *progress) Completed() int {
+	return int(p.completed.Load())
+}
+
+// Appended returns the number of closers appended over the lifetime of the closure.
+// Exported as a counter, it makes a leak of per-request closers into a long-lived closure visible.
+func (p *progress) Appended() int {
+	return int(p.appended.Load())
+}
+
+// Removed returns the number of closers removed over the lifetime of the closure,
+// e.g. moved to another closure by SetPackageClosure.
+func (p *progress) Removed() int {
+	return int(p.removed.Load())
+}
+
+// added records a newly appended closer.
+func (p *progress) added() {
+	p.pending.Add(1)
+	p.appended.Add(1)
+}
+
+// drained records the removal of n closers.
+func (p *progress) drained(n int) {
+	p.removed.Add(int64(n))
+	p.start(0)
+}
+
+// start resets the counters at the beginning of a close sequence of n closers.
+func (p *progress) start(n int) {
+	p.pending.Store(int64(n))
+	p.completed.Store(0)
+}
+
+// done records a closed closer.
+func (p *progress) done() {
+	p.pending.Add(-1)
+	p.completed.Add(1)
+}
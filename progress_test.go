@@ -0,0 +1,66 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	closures := map[string]interface {
+		Closure
+		Pending() int
+		Completed() int
+	}{
+		"lifo":  &Lifo{},
+		"fifo":  &Fifo{},
+		"group": &Group{},
+	}
+
+	for name, closure := range closures {
+		closure := closure
+
+		t.Run(name, func(t *testing.T) {
+			release := make(chan struct{})
+
+			closure.Append(&mockCloser{})
+			closure.Append(Fn(func() error {
+				<-release
+				return nil
+			}))
+			closure.Append(&mockCloser{})
+			assert.Equal(t, 3, closure.Pending())
+			assert.Equal(t, 0, closure.Completed())
+
+			done := make(chan error)
+			go func() { done <- closure.Close() }()
+
+			assert.Eventually(t, func() bool {
+				// The blocked closer stays pending, the order of the others depends on the strategy.
+				return closure.Completed() >= 1 && closure.Pending() >= 1 && closure.Pending()+closure.Completed() == 3
+			}, time.Second, time.Millisecond)
+
+			close(release)
+			assert.NoError(t, <-done)
+			assert.Equal(t, 0, closure.Pending())
+			assert.Equal(t, 3, closure.Completed())
+		})
+	}
+}
+
+func TestProgress_Churn(t *testing.T) {
+	lifo := &Lifo{}
+	lifo.Append(&mockCloser{})
+	lifo.Append(&mockCloser{})
+
+	assert.Equal(t, 2, lifo.Appended())
+	assert.Zero(t, lifo.Removed())
+
+	lifo.drain()
+	lifo.Append(&mockCloser{})
+
+	assert.Equal(t, 3, lifo.Appended())
+	assert.Equal(t, 2, lifo.Removed())
+	assert.Equal(t, 1, lifo.Pending())
+}
@@ -0,0 +1,71 @@
+package shutdown
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"runtime/pprof"
+	"sync/atomic"
+	"syscall"
+)
+
+// QuitMode defines how SIGQUIT is handled when it is passed to the signal helpers.
+type QuitMode int32
+
+const (
+	// QuitGraceful treats SIGQUIT like any other signal: it triggers the graceful shutdown.
+	QuitGraceful QuitMode = iota
+	// QuitPassthrough leaves SIGQUIT at its default disposition (goroutine dump and exit),
+	// by not registering it even if it is passed to the signal helpers.
+	QuitPassthrough
+	// QuitDump emulates the default disposition: on SIGQUIT the goroutines are dumped
+	// to the quit dump writer (os.Stderr by default) before the graceful shutdown.
+	QuitDump
+)
+
+var (
+	quitMode   atomic.Int32             // The current QuitMode
+	quitWriter io.Writer    = os.Stderr // Writer of the goroutine dump in QuitDump mode
+)
+
+// SetQuitMode sets how SIGQUIT is handled by WaitForSignals, WaitForSignalsContext and SignalTrigger.
+// Registering SIGQUIT for a graceful shutdown otherwise silently removes the runtime's goroutine dump.
+func SetQuitMode(mode QuitMode) {
+	quitMode.Store(int32(mode))
+}
+
+// filterSignals removes SIGQUIT from the signals in QuitPassthrough mode.
+func filterSignals(sig []os.Signal) []os.Signal {
+	if QuitMode(quitMode.Load()) != QuitPassthrough {
+		return sig
+	}
+
+	filtered := make([]os.Signal, 0, len(sig))
+
+	for _, s := range sig {
+		if s != syscall.SIGQUIT {
+			filtered = append(filtered, s)
+		}
+	}
+
+	return filtered
+}
+
+// notifySignals registers the signals, filtered by filterSignals, to the channel.
+// If all of them are filtered out, nothing is registered: signal.Notify without signals
+// would relay every incoming signal to the channel.
+func notifySignals(c chan<- os.Signal, sig []os.Signal) {
+	filtered := filterSignals(sig)
+	if len(filtered) == 0 && len(sig) > 0 {
+		return
+	}
+
+	signal.Notify(c, filtered...)
+}
+
+// handleQuit dumps the goroutines in QuitDump mode if the received signal is SIGQUIT.
+func handleQuit(s os.Signal) {
+	if s == syscall.SIGQUIT && QuitMode(quitMode.Load()) == QuitDump {
+		_ = pprof.Lookup("goroutine").WriteTo(quitWriter, 2) // The same format as the runtime's dump
+	}
+}
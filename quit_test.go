@@ -0,0 +1,68 @@
+package shutdown
+
+import (
+	"bytes"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterSignals(t *testing.T) {
+	defer SetQuitMode(QuitGraceful)
+
+	sig := []os.Signal{os.Interrupt, syscall.SIGQUIT}
+	assert.Equal(t, sig, filterSignals(sig))
+
+	SetQuitMode(QuitPassthrough)
+	assert.Equal(t, []os.Signal{os.Interrupt}, filterSignals(sig))
+}
+
+func TestNotifySignals_AllFiltered(t *testing.T) {
+	defer SetQuitMode(QuitGraceful)
+
+	guard := make(chan os.Signal, 1) // Keeps SIGUSR1 from terminating the test
+	signal.Notify(guard, syscall.SIGUSR1)
+	defer signal.Stop(guard)
+
+	SetQuitMode(QuitPassthrough)
+
+	c := make(chan os.Signal, 1)
+	notifySignals(c, []os.Signal{syscall.SIGQUIT})
+	defer signal.Stop(c)
+
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+
+	select {
+	case <-guard:
+	case <-time.After(time.Second):
+		t.Fatal("SIGUSR1 is not received")
+	}
+
+	select {
+	case s := <-c:
+		t.Fatalf("unexpected signal %s", s)
+	default: // No signal is registered
+	}
+}
+
+func TestHandleQuit(t *testing.T) {
+	defer SetQuitMode(QuitGraceful)
+
+	buf := &bytes.Buffer{}
+	quitWriter = buf
+	defer func() { quitWriter = os.Stderr }()
+
+	handleQuit(syscall.SIGQUIT)
+	assert.Zero(t, buf.Len())
+
+	SetQuitMode(QuitDump)
+	handleQuit(os.Interrupt)
+	assert.Zero(t, buf.Len())
+
+	handleQuit(syscall.SIGQUIT)
+	assert.Contains(t, buf.String(), "goroutine")
+}
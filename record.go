@@ -0,0 +1,112 @@
+package shutdown
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// reportRecord is the JSON representation of a Report.
+type reportRecord struct {
+	RunID     string         `json:"run_id,omitempty"`
+	Deadline  DeadlineSource `json:"deadline_source,omitempty"`
+	Started   time.Time      `json:"started"`
+	Duration  string         `json:"duration"`
+	Error     string         `json:"error,omitempty"`
+	Summary   string         `json:"summary,omitempty"`
+	Results   []resultRecord `json:"results"`
+	Anomalies []string       `json:"anomalies,omitempty"`
+}
+
+// resultRecord is the JSON representation of a Result.
+type resultRecord struct {
+	Name     string   `json:"name,omitempty"`
+	Package  string   `json:"package,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+	Timeout  string   `json:"timeout,omitempty"`
+	Duration string   `json:"duration"`
+	Error    string   `json:"error,omitempty"`
+	Skipped  bool     `json:"skipped,omitempty"`
+}
+
+// newReportRecord returns the JSON representation of the report and the error of the close sequence.
+func newReportRecord(r *Report, err error) reportRecord {
+	record := reportRecord{
+		RunID:    r.RunID,
+		Deadline: r.Deadline,
+		Started:  r.Started,
+		Duration: r.Duration.String(),
+		Summary:  r.Summary(),
+		Results:  make([]resultRecord, 0, len(r.Results)),
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	for _, res := range r.Results {
+		rr := resultRecord{
+			Name:     res.Name,
+			Package:  res.Package,
+			Tags:     res.Tags,
+			Priority: res.Priority,
+			Duration: res.Duration.String(),
+			Skipped:  res.Skipped,
+		}
+
+		if res.Timeout > 0 {
+			rr.Timeout = res.Timeout.String()
+		}
+
+		if res.Err != nil {
+			rr.Error = res.Err.Error()
+		}
+
+		record.Results = append(record.Results, rr)
+	}
+
+	for _, a := range r.Anomalies {
+		record.Anomalies = append(record.Anomalies, a.String())
+	}
+
+	return record
+}
+
+// MarshalJSON encodes the report with the errors as strings and the durations in the time.Duration format.
+func (r *Report) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newReportRecord(r, nil))
+}
+
+// reportFile writes the report of every close sequence of the wrapped Closure2 to a file.
+type reportFile struct {
+	Closure2        // The wrapped closure
+	path     string // The path of the file
+}
+
+// WithReportFile wraps the closure so that the report of its close sequence, including the overall error,
+// is written as JSON to the file at path, atomically, so node-level agents and postmortem tooling can ingest
+// the last known shutdown status even when the logs were lost. A failure to write the file is returned
+// along with the close errors.
+func WithReportFile(c Closure2, path string) Closure2 {
+	return &reportFile{Closure2: c, path: path}
+}
+
+// CloseContext closes the wrapped closure and writes its report to the file.
+func (f *reportFile) CloseContext(ctx context.Context) (*Report, error) {
+	report, err := f.Closure2.CloseContext(ctx)
+
+	data, marshalErr := json.MarshalIndent(newReportRecord(report, err), "", "  ")
+	if marshalErr != nil {
+		return report, multierr.Append(err, fmt.Errorf("shutdown: cannot marshal report: %w", marshalErr))
+	}
+
+	if writeErr := writeFileAtomic(f.path, append(data, '\n')); writeErr != nil {
+		return report, multierr.Append(err, fmt.Errorf("shutdown: cannot write report: %w", writeErr))
+	}
+
+	return report, err
+}
@@ -0,0 +1,59 @@
+package shutdown
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithReportFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shutdown.json")
+
+	closure := WithReportFile(Upgrade(&Fifo{}), path)
+	closure.Append(&mockCloser{}, WithName("http"), WithTimeout(time.Second), WithTags("network"))
+	closure.Append(Fn(func() error { return errors.New("disk full") }), WithName("db"))
+
+	ctx := RunIDToContext(context.Background(), "run-1")
+
+	_, err := closure.CloseContext(ctx)
+	require.Error(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var record reportRecord
+	require.NoError(t, json.Unmarshal(data, &record))
+
+	assert.Equal(t, "run-1", record.RunID)
+	assert.Equal(t, "db: disk full", record.Error)
+	assert.Equal(t, "untagged: 1 error", record.Summary)
+
+	if assert.Len(t, record.Results, 2) {
+		assert.Equal(t, "http", record.Results[0].Name)
+		assert.Equal(t, "1s", record.Results[0].Timeout)
+		assert.Equal(t, []string{"network"}, record.Results[0].Tags)
+		assert.Empty(t, record.Results[0].Error)
+		assert.Equal(t, "db: disk full", record.Results[1].Error)
+	}
+}
+
+func TestWithReportFile_WriteError(t *testing.T) {
+	closure := WithReportFile(Upgrade(&Fifo{}), filepath.Join(t.TempDir(), "missing", "shutdown.json"))
+
+	_, err := closure.CloseContext(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "shutdown: cannot write report")
+}
+
+func TestReport_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(&Report{Results: []Result{{Entry: Entry{Name: "db"}, Err: errors.New("boom")}}})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"error":"boom"`)
+}
@@ -0,0 +1,104 @@
+package shutdown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// closerKey is the context key of the name of the closer being closed during the shutdown.
+type closerKey struct{}
+
+var (
+	runningMx sync.Mutex        // Mutex for thread safety of running
+	running   map[uint64]string // Names of the closers being closed during the shutdown, by goroutine ID
+)
+
+// enterCloser marks the context of the closer being closed during the shutdown, so that
+// a call back into the package-level CloseContext is recognized, and marks the calling goroutine,
+// see markGoroutine. The returned function removes the goroutine mark.
+func enterCloser(ctx context.Context, closer Closer) (context.Context, func()) {
+	if !closing.Load() {
+		return ctx, func() {}
+	}
+
+	ctx = context.WithValue(ctx, closerKey{}, describe(closer, 0).Name)
+
+	return ctx, markGoroutine(ctx)
+}
+
+// markGoroutine registers the calling goroutine as running the closer marked in the context, if any,
+// so that a call of the package-level Close, which carries no context, is recognized as reentrant.
+// The goroutines started to run a closer, e.g. the one of a closer having its own timeout, mark themselves.
+// The returned function removes the mark.
+func markGoroutine(ctx context.Context) func() {
+	name, ok := ctx.Value(closerKey{}).(string)
+	if !ok || !closing.Load() {
+		return func() {}
+	}
+
+	id := goroutineID()
+
+	runningMx.Lock()
+	defer runningMx.Unlock()
+
+	if running == nil {
+		running = make(map[uint64]string)
+	}
+
+	prev, nested := running[id]
+	running[id] = name
+
+	return func() {
+		runningMx.Lock()
+		defer runningMx.Unlock()
+
+		if nested {
+			running[id] = prev // Back to the closer of the enclosing closure
+		} else {
+			delete(running, id)
+		}
+	}
+}
+
+// reentrant returns ErrReentrantClose naming the offending closer if the package-level Close
+// or CloseContext is called by a closer of the running shutdown, nil otherwise. The call is recognized
+// by the context passed to the closer, or, for the closers calling Close, by the mark of the calling goroutine.
+// Calls from other goroutines are not reentrant: they wait for the shutdown to finish.
+func reentrant(ctx context.Context) error {
+	if !closing.Load() {
+		return nil
+	}
+
+	name, ok := ctx.Value(closerKey{}).(string)
+	if !ok {
+		runningMx.Lock()
+		name, ok = running[goroutineID()]
+		runningMx.Unlock()
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return fmt.Errorf("%w by closer %s", ErrReentrantClose, name)
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the header of its stack trace,
+// e.g. "goroutine 18 [running]:".
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+
+	return id
+}
@@ -0,0 +1,70 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/partyzanex/shutdown/backoff"
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// ErrNotReleased is returned (wrapped) by a VerifiedCloser when the resource is still held after closing.
+var ErrNotReleased = errors.New("shutdown: resource not released")
+
+// VerifiedCloser closes the wrapped closer, then verifies that the resource is really released.
+type VerifiedCloser struct {
+	closer Closer                          // The wrapped closer
+	verify func(ctx context.Context) error // Returns an error while the resource is still held
+	cfg    backoff.Config                  // The delays between the verification attempts
+}
+
+// CloseAndVerify returns a closer that closes c once, then calls verify up to attempts times
+// with an exponential backoff until it reports the resource as released, e.g. the lock file is gone
+// or the lease is revoked. It is intended for storage engines, where Close returning nil does not guarantee
+// the release: a process restarting right after would fail to acquire the resource.
+// Zero or negative attempts means backoff.DefaultAttempts.
+func CloseAndVerify(c Closer, verify func(ctx context.Context) error, attempts int) *VerifiedCloser {
+	return &VerifiedCloser{
+		closer: c,
+		verify: verify,
+		cfg:    backoff.Config{Attempts: attempts}.WithDefaults(),
+	}
+}
+
+// CloseContext closes the wrapped closer and verifies the release within the deadline of ctx.
+// The error of the closer is returned as is, without verification. If the resource is still held
+// once the attempts are exhausted or the context is done, the error of the last verification
+// is returned wrapped with ErrNotReleased.
+func (v *VerifiedCloser) CloseContext(ctx context.Context) error {
+	if err := closeCtx(ctx, v.closer); err != nil {
+		return err
+	}
+
+	var err error
+
+	for attempt := 0; attempt < v.cfg.Attempts; attempt++ {
+		if delay := v.cfg.Delay(attempt); delay > 0 {
+			timer := time.NewTimer(delay)
+
+			select {
+			case <-ctx.Done(): // If the context is cancelled or times out.
+				timer.Stop()
+				return multierr.Append(fmt.Errorf("%w: %w", ErrNotReleased, err), ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		if err = v.verify(ctx); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %w", ErrNotReleased, err)
+}
+
+// Close closes the wrapped closer and verifies the release without a deadline.
+func (v *VerifiedCloser) Close() error {
+	return v.CloseContext(context.Background())
+}
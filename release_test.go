@@ -0,0 +1,76 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseAndVerify(t *testing.T) {
+	var (
+		closed bool
+		checks int
+	)
+
+	c := CloseAndVerify(Fn(func() error {
+		closed = true
+		return nil
+	}), func(context.Context) error {
+		checks++
+
+		if checks < 2 {
+			return errors.New("lock file exists")
+		}
+
+		return nil
+	}, 3)
+	c.cfg.Initial = time.Millisecond
+
+	require.NoError(t, c.Close())
+	assert.True(t, closed)
+	assert.Equal(t, 2, checks)
+}
+
+func TestCloseAndVerify_NotReleased(t *testing.T) {
+	held := errors.New("lease held")
+	checks := 0
+
+	c := CloseAndVerify(Fn(func() error { return nil }), func(context.Context) error {
+		checks++
+		return held
+	}, 2)
+	c.cfg.Initial = time.Millisecond
+
+	err := c.Close()
+	assert.ErrorIs(t, err, ErrNotReleased)
+	assert.ErrorIs(t, err, held)
+	assert.Equal(t, 2, checks)
+}
+
+func TestCloseAndVerify_CloseError(t *testing.T) {
+	closeErr := errors.New("close failed")
+
+	c := CloseAndVerify(Fn(func() error { return closeErr }), func(context.Context) error {
+		t.Fatal("Expected no verification after a failed close")
+		return nil
+	}, 2)
+
+	assert.Equal(t, closeErr, c.Close())
+}
+
+func TestCloseAndVerify_Deadline(t *testing.T) {
+	c := CloseAndVerify(Fn(func() error { return nil }), func(context.Context) error {
+		return errors.New("lease held")
+	}, 5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := c.CloseContext(ctx)
+	assert.ErrorIs(t, err, ErrNotReleased)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,173 @@
+// Package remote lets plugins running in subprocesses register named shutdown steps with their host
+// over net/rpc. The plugin exports its steps with their metadata, the host imports them into its own
+// closure, which orchestrates the ordering across the process boundaries and calls the plugin back
+// to run each step.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/partyzanex/shutdown"
+)
+
+// ServiceName is the name of the RPC service served by the plugins.
+const ServiceName = "ShutdownPlugin"
+
+var (
+	// ErrDuplicateStep is returned by Exporter.Register when a step of the same name is already registered.
+	ErrDuplicateStep = errors.New("remote: duplicate step")
+	// ErrUnnamedStep is returned by Exporter.Register when the step has no name.
+	ErrUnnamedStep = errors.New("remote: step without a name")
+	// ErrUnknownStep is returned to the host closing a step the plugin has not registered.
+	ErrUnknownStep = errors.New("remote: unknown step")
+)
+
+// Step is the metadata of a shutdown step exported by a plugin, mirroring shutdown.Entry.
+type Step struct {
+	Name     string        // Unique name of the step within the plugin, required
+	Timeout  time.Duration // Maximum close duration of the step, zero means no own timeout
+	Priority int           // Priority of the step, honored by the host strategies supporting priorities
+	Tags     []string      // Arbitrary tags of the step
+}
+
+// CloseRequest asks a plugin to run one of its steps.
+type CloseRequest struct {
+	Name     string    // The name of the step
+	Deadline time.Time // The deadline of the step on the host, zero means none
+}
+
+// Exporter holds the shutdown steps of a plugin and serves them to the host.
+type Exporter struct {
+	steps   []Step                     // The registered steps, in registration order
+	closers map[string]shutdown.Closer // The closers of the steps by name
+	mx      sync.Mutex                 // Mutex for thread safety
+}
+
+// NewExporter creates a new Exporter.
+func NewExporter() *Exporter {
+	return &Exporter{closers: make(map[string]shutdown.Closer)}
+}
+
+// Register adds the closer as a named step, run when the host closes it.
+func (e *Exporter) Register(step Step, closer shutdown.Closer) error {
+	if step.Name == "" {
+		return ErrUnnamedStep
+	}
+
+	e.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer e.mx.Unlock() // Release the lock after the function finishes.
+
+	if _, ok := e.closers[step.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateStep, step.Name)
+	}
+
+	e.steps = append(e.steps, step)
+	e.closers[step.Name] = closer
+
+	return nil
+}
+
+// ServeConn serves the steps to the host on the connection, e.g. the stdio of the plugin process,
+// and blocks until the host hangs up.
+func (e *Exporter) ServeConn(conn io.ReadWriteCloser) {
+	srv := rpc.NewServer()
+	_ = srv.RegisterName(ServiceName, &service{exporter: e}) // The methods of service are valid by construction
+
+	srv.ServeConn(conn)
+}
+
+// service is the RPC receiver of an Exporter.
+type service struct {
+	exporter *Exporter
+}
+
+// Steps returns the registered steps.
+func (s *service) Steps(_ int, reply *[]Step) error {
+	s.exporter.mx.Lock()
+	defer s.exporter.mx.Unlock()
+
+	*reply = append([]Step(nil), s.exporter.steps...)
+
+	return nil
+}
+
+// Close runs the requested step within its deadline on the host.
+func (s *service) Close(req CloseRequest, _ *bool) error {
+	s.exporter.mx.Lock()
+	closer, ok := s.exporter.closers[req.Name]
+	s.exporter.mx.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownStep, req.Name)
+	}
+
+	ctx := context.Background()
+
+	if !req.Deadline.IsZero() {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithDeadline(ctx, req.Deadline)
+		defer cancel()
+	}
+
+	if c, ok := closer.(shutdown.ContextCloser); ok {
+		return c.CloseContext(ctx)
+	}
+
+	return closer.Close()
+}
+
+// Import fetches the steps of the plugin served on the client and appends a closer running each of them
+// to the closure, with the name, timeout, priority and tags of the step. The names are prefixed
+// with the name of the plugin, e.g. "billing/flush", so the reports tell the plugins apart.
+func Import(client *rpc.Client, plugin string, c shutdown.Closure2) error {
+	var steps []Step
+
+	if err := client.Call(ServiceName+".Steps", 0, &steps); err != nil {
+		return fmt.Errorf("remote: %s: list steps: %w", plugin, err)
+	}
+
+	for _, step := range steps {
+		c.Append(&stepCloser{client: client, name: step.Name},
+			shutdown.WithName(plugin+"/"+step.Name),
+			shutdown.WithTimeout(step.Timeout),
+			shutdown.WithPriority(step.Priority),
+			shutdown.WithTags(step.Tags...),
+		)
+	}
+
+	return nil
+}
+
+// stepCloser runs a step of a plugin.
+type stepCloser struct {
+	client *rpc.Client // The client of the plugin
+	name   string      // The name of the step
+}
+
+// CloseContext asks the plugin to run the step within the deadline of ctx,
+// and gives up waiting once the context is done.
+func (s *stepCloser) CloseContext(ctx context.Context) error {
+	req := CloseRequest{Name: s.name}
+	req.Deadline, _ = ctx.Deadline()
+
+	call := s.client.Go(ServiceName+".Close", req, new(bool), make(chan *rpc.Call, 1))
+
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out.
+		return ctx.Err()
+	case <-call.Done:
+		return call.Error
+	}
+}
+
+// Close asks the plugin to run the step without a deadline.
+func (s *stepCloser) Close() error {
+	return s.CloseContext(context.Background())
+}
@@ -0,0 +1,82 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/partyzanex/shutdown"
+)
+
+// deadlineCloser records the deadline of its context.
+type deadlineCloser struct {
+	deadline time.Time
+	ok       bool
+}
+
+func (d *deadlineCloser) CloseContext(ctx context.Context) error {
+	d.deadline, d.ok = ctx.Deadline()
+	return nil
+}
+
+func (d *deadlineCloser) Close() error {
+	return d.CloseContext(context.Background())
+}
+
+func TestImport(t *testing.T) {
+	var order []string
+
+	exporter := NewExporter()
+	flush := &deadlineCloser{}
+
+	require.NoError(t, exporter.Register(Step{Name: "flush", Timeout: time.Second, Priority: 1}, flush))
+	require.NoError(t, exporter.Register(Step{Name: "close", Tags: []string{"storage"}}, shutdown.Fn(func() error {
+		order = append(order, "close")
+		return errors.New("disk busy")
+	})))
+	assert.ErrorIs(t, exporter.Register(Step{Name: "flush"}, flush), ErrDuplicateStep)
+	assert.ErrorIs(t, exporter.Register(Step{}, flush), ErrUnnamedStep)
+
+	host, plugin := net.Pipe()
+	go exporter.ServeConn(plugin)
+
+	client := rpc.NewClient(host)
+	defer client.Close()
+
+	closure := shutdown.Upgrade(&shutdown.Priority{})
+	require.NoError(t, Import(client, "billing", closure))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	report, err := closure.CloseContext(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "billing/close: disk busy")
+	assert.Equal(t, []string{"close"}, order)
+
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "billing/flush", report.Results[0].Name)
+	assert.Equal(t, 1, report.Results[0].Priority)
+	assert.Equal(t, []string{"storage"}, report.Results[1].Tags)
+
+	assert.True(t, flush.ok, "Expected the step to get the deadline of the host")
+	assert.WithinDuration(t, time.Now().Add(time.Second), flush.deadline, time.Second)
+}
+
+func TestImport_UnknownStep(t *testing.T) {
+	host, plugin := net.Pipe()
+	go NewExporter().ServeConn(plugin)
+
+	client := rpc.NewClient(host)
+	defer client.Close()
+
+	err := (&stepCloser{client: client, name: "missing"}).Close()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "remote: unknown step: missing")
+}
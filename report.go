@@ -0,0 +1,102 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of closing a single closer.
+type Result struct {
+	Entry
+
+	Duration time.Duration // How long the closing took
+	Err      error         // The error returned by the closer, if any
+	Skipped  bool          // Whether the closer was skipped, see SkipIf
+}
+
+// Report describes a close sequence of a Closure2.
+type Report struct {
+	RunID     string         // The shutdown run ID, if the context carried one
+	Deadline  DeadlineSource // The source of the deadline, if the context carried one
+	Started   time.Time      // When the close sequence started
+	Duration  time.Duration  // How long the close sequence took
+	Results   []Result       // Results of the closed closers, in completion order
+	Cancelled error          // The error of the context once the close sequence finished, nil if it was not done
+
+	// Anomalies are the clock anomalies observed during the close sequence, e.g. a pause of the process,
+	// by which the timeouts of the closers running meanwhile were extended.
+	Anomalies []Anomaly
+}
+
+// recorderKey is the context key of the recorder of the package-level close sequence.
+type recorderKey struct{}
+
+// recorder collects the results of the closers of the package-level close sequence.
+type recorder struct {
+	mx      sync.Mutex // Mutex for thread safety
+	results []Result   // The results, in completion order
+	sealed  bool       // Whether the report is built, the closers finishing later are not recorded
+	parent  *recorder  // The recorder the results are also passed to, may be nil
+}
+
+// recorderFromContext returns the recorder of the close sequence, nil if none.
+func recorderFromContext(ctx context.Context) *recorder {
+	r, _ := ctx.Value(recorderKey{}).(*recorder)
+	return r
+}
+
+// closeRecorded closes the closer with the policy, recording its result if ctx carries a recorder.
+// The closers of nested closures are not recorded, the nested closure is reported as a whole.
+func closeRecorded(ctx context.Context, closer Closer, policy PanicPolicy) error {
+	r := recorderFromContext(ctx)
+	if r == nil {
+		return closeWithPolicy(ctx, closer, policy)
+	}
+
+	entry, skip := resultEntry(closer), decideSkip(closer)
+	ctx = context.WithValue(withSkipDecision(ctx, skip), recorderKey{}, (*recorder)(nil))
+
+	start := time.Now()
+	err := closeWithPolicy(ctx, closer, policy)
+	r.record(Result{Entry: entry, Duration: time.Since(start), Err: err, Skipped: skip.skip})
+
+	return err
+}
+
+// record stores the result, unless the report is already built, and passes it to the parent recorder.
+func (r *recorder) record(result Result) {
+	r.mx.Lock()
+	if !r.sealed {
+		r.results = append(r.results, result)
+	}
+	r.mx.Unlock()
+
+	if r.parent != nil {
+		r.parent.record(result)
+	}
+}
+
+// seal returns the recorded results and stops recording.
+func (r *recorder) seal() []Result {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.sealed = true
+
+	return r.results
+}
+
+// resultEntry returns the Entry of the closer: the one given to a Closure2, or else one described from the closer.
+func resultEntry(closer Closer) Entry {
+	if e, ok := closer.(*entryCloser); ok {
+		entry := e.entry
+		entry.Name = describe(closer, 0).Name
+
+		return entry
+	}
+
+	step := describe(closer, 0)
+
+	return Entry{Name: step.Name, Timeout: step.Timeout, Tags: step.Tags}
+}
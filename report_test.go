@@ -0,0 +1,40 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	parent := &recorder{}
+	r := &recorder{parent: parent}
+
+	r.record(Result{Entry: Entry{Name: "db"}})
+	assert.Len(t, r.seal(), 1)
+
+	r.record(Result{Entry: Entry{Name: "late"}}) // Not recorded once sealed, still passed to the parent
+	assert.Len(t, r.seal(), 1)
+	assert.Len(t, parent.seal(), 2)
+}
+
+func TestCloseRecorded(t *testing.T) {
+	expectedErr := errors.New("close error")
+	closer := &timedCloser{closer: Named("db", &mockCloser{closeFunc: func() error { return expectedErr }}), timeout: time.Second}
+
+	// Without a recorder, the closer is closed only.
+	assert.ErrorIs(t, closeRecorded(context.Background(), closer, PanicContinue), expectedErr)
+
+	r := &recorder{}
+	ctx := context.WithValue(context.Background(), recorderKey{}, r)
+	assert.ErrorIs(t, closeRecorded(ctx, closer, PanicContinue), expectedErr)
+
+	if results := r.seal(); assert.Len(t, results, 1) {
+		assert.Equal(t, "db", results[0].Name)
+		assert.Equal(t, time.Second, results[0].Timeout)
+		assert.ErrorIs(t, results[0].Err, expectedErr)
+	}
+}
@@ -0,0 +1,55 @@
+package shutdown
+
+import (
+	"context"
+	"time"
+
+	"github.com/partyzanex/shutdown/backoff"
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// BackoffCloser retries closing the wrapped closer with an exponential backoff.
+type BackoffCloser struct {
+	closer Closer         // The wrapped closer
+	cfg    backoff.Config // The backoff configuration
+}
+
+// WithBackoffCloser returns a closer that retries closing c with an exponential backoff
+// until it succeeds, the attempts are exhausted, or the context is done.
+// It is intended for one-off flushes over flaky networks.
+func WithBackoffCloser(c Closer, cfg backoff.Config) *BackoffCloser {
+	return &BackoffCloser{closer: c, cfg: cfg.WithDefaults()}
+}
+
+// CloseContext retries closing the wrapped closer until it succeeds, the attempts are exhausted,
+// or the context is done. The errors of all failed attempts are returned.
+func (b *BackoffCloser) CloseContext(ctx context.Context) error {
+	var errs error
+
+	for attempt := 0; attempt < b.cfg.Attempts; attempt++ {
+		if delay := b.cfg.Delay(attempt); delay > 0 {
+			timer := time.NewTimer(delay)
+
+			select {
+			case <-ctx.Done(): // If the context is cancelled or times out.
+				timer.Stop()
+				return multierr.Append(errs, ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		err := closeCtx(ctx, b.closer)
+		if err == nil {
+			return nil
+		}
+
+		errs = multierr.Append(errs, err)
+	}
+
+	return errs
+}
+
+// Close retries closing the wrapped closer without a deadline.
+func (b *BackoffCloser) Close() error {
+	return b.CloseContext(context.Background())
+}
@@ -0,0 +1,45 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
+
+	"github.com/partyzanex/shutdown/backoff"
+)
+
+func TestWithBackoffCloser(t *testing.T) {
+	attempts := 0
+	closer := Fn(func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("flush error")
+		}
+		return nil
+	})
+
+	err := WithBackoffCloser(closer, backoff.Config{Attempts: 3, Initial: time.Millisecond}).Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestWithBackoffCloser_Exhausted(t *testing.T) {
+	closer := Fn(func() error { return errors.New("flush error") })
+
+	err := WithBackoffCloser(closer, backoff.Config{Attempts: 2, Initial: time.Millisecond}).Close()
+	assert.Len(t, multierr.Errors(err), 2)
+}
+
+func TestWithBackoffCloser_Context(t *testing.T) {
+	closer := Fn(func() error { return errors.New("flush error") })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := WithBackoffCloser(closer, backoff.Config{Attempts: 10, Initial: time.Second}).CloseContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,204 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxScheduleSearch bounds the search for the next time matching a schedule, e.g. "0 0 30 2 *" never matches.
+const maxScheduleSearch = 5 // Years
+
+// scheduleDescriptors are the predefined schedules.
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// scheduleFields are the fields of a cron schedule, in order, with their ranges.
+var scheduleFields = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // Both 0 and 7 are Sunday
+}
+
+// schedule is a parsed cron schedule: the bit sets of the allowed values of its fields.
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+	anyDay                        bool // Whether the day of month or the day of week is unrestricted
+}
+
+// parseSchedule parses a cron schedule, see ScheduleTrigger.
+func parseSchedule(spec string) (*schedule, error) {
+	expr := spec
+	if d, ok := scheduleDescriptors[expr]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(scheduleFields) {
+		return nil, fmt.Errorf("shutdown: invalid schedule %q: expected %d fields", spec, len(scheduleFields))
+	}
+
+	var sets [len(scheduleFields)]uint64
+
+	for i, field := range fields {
+		set, err := parseScheduleField(field, scheduleFields[i].min, scheduleFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("shutdown: invalid schedule %q: %s: %w", spec, scheduleFields[i].name, err)
+		}
+
+		sets[i] = set
+	}
+
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1 // Sunday
+	}
+
+	return &schedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		anyDay: fields[2] == "*" || fields[4] == "*",
+	}, nil
+}
+
+// parseScheduleField returns the bit set of the values of the field within [min, max].
+func parseScheduleField(field string, min, max int) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(field, ",") {
+		values, stepValue, hasStep := strings.Cut(part, "/")
+
+		step := 1
+
+		if hasStep {
+			var err error
+
+			if step, err = strconv.Atoi(stepValue); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepValue)
+			}
+		}
+
+		lo, hi := min, max
+
+		if values != "*" {
+			first, last, isRange := strings.Cut(values, "-")
+
+			var err error
+
+			if lo, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("invalid value %q", first)
+			}
+
+			switch {
+			case isRange:
+				if hi, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("invalid value %q", last)
+				}
+			case !hasStep:
+				hi = lo // A single value, or else the start of a step, e.g. "5/10"
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range [%d, %d]", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+// next returns the first minute after t matching the schedule, in the location of t,
+// or the zero time if none matches within maxScheduleSearch years.
+func (s *schedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	limit := t.AddDate(maxScheduleSearch, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchDay reports whether the day of t matches the schedule. Like cron, if both the day of month
+// and the day of week are restricted, a day matching either of them matches.
+func (s *schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDay {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// ScheduleTrigger returns a trigger that fires at the next time matching the cron schedule
+// in the local time zone, plus a random jitter in [0, jitter) chosen once per trigger, so a fleet
+// recycling its processes on a schedule, e.g. "0 4 * * *" for every night at 4:00,
+// does not restart all instances at the same instant. The schedule has the standard five fields
+// "minute hour day-of-month month day-of-week", each a list of values, ranges and steps
+// (e.g. "*/15", "1-5", "0,30"), or is one of the descriptors @yearly, @monthly, @weekly, @daily and @hourly.
+// It returns an error if the schedule is invalid.
+func ScheduleTrigger(spec string, jitter time.Duration) (Trigger, error) {
+	s, err := parseSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var offset time.Duration
+
+	if jitter > 0 {
+		offset = time.Duration(rand.Int63n(int64(jitter))) //nolint:gosec // jitter does not need a secure random
+	}
+
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		next := s.next(time.Now())
+		if next.IsZero() {
+			return "", fmt.Errorf("shutdown: schedule %q never matches", spec)
+		}
+
+		timer := time.NewTimer(time.Until(next.Add(offset)))
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timer.C:
+			return "schedule " + spec, nil
+		}
+	}), nil
+}
@@ -0,0 +1,54 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 22, 47, 30, 0, time.UTC) // Wednesday
+
+	for spec, expected := range map[string]time.Time{
+		"* * * * *":       time.Date(2024, time.January, 31, 22, 48, 0, 0, time.UTC),
+		"*/15 * * * *":    time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC),
+		"0 4 * * *":       time.Date(2024, time.February, 1, 4, 0, 0, 0, time.UTC),
+		"@monthly":        time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"30 2 29 2 *":     time.Date(2024, time.February, 29, 2, 30, 0, 0, time.UTC),
+		"0 0 * * 7":       time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC), // Sunday
+		"0 0 15 * 1-5":    time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), // A weekday, or the 15th
+		"5/20 9-17 * * *": time.Date(2024, time.February, 1, 9, 5, 0, 0, time.UTC),
+	} {
+		s, err := parseSchedule(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, expected, s.next(from), spec)
+	}
+
+	s, err := parseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.next(from).IsZero())
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := parseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestScheduleTrigger(t *testing.T) {
+	_, err := ScheduleTrigger("@never", 0)
+	assert.Error(t, err)
+
+	trigger, err := ScheduleTrigger("@yearly", time.Minute)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = trigger.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,204 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// ErrSessionsClosed is returned when a session is tracked by a SessionRegistry that is shutting down.
+var ErrSessionsClosed = errors.New("shutdown: sessions closed")
+
+// DefaultSessionNotice is the termination notice sent to the sessions if none is configured.
+const DefaultSessionNotice = "The server is shutting down, please save your work and disconnect."
+
+// Session is an interactive session, e.g. an SSH session or a debug console.
+type Session interface {
+	Notify(notice string) error // Sends the termination notice to the user of the session
+	Close() error               // Forcibly terminates the session
+}
+
+// SessionStats reports the outcome of closing a SessionRegistry.
+type SessionStats struct {
+	Notified     int // Number of sessions the termination notice was sent to
+	Disconnected int // Number of sessions ended by their users within the grace period
+	Terminated   int // Number of sessions forcibly terminated
+}
+
+// SessionRegistry tracks interactive sessions of a bastion-style service. On shutdown it sends
+// a termination notice to each session, waits a grace period for the users to disconnect,
+// and then forcibly terminates the remaining sessions.
+type SessionRegistry struct {
+	grace    time.Duration             // Time given to the users to disconnect
+	notice   string                    // The termination notice
+	sessions map[*sessionEntry]Session // The tracked sessions
+	closing  bool                      // Whether the registry is shutting down
+	empty    chan struct{}             // Closed once the registry becomes empty during the shutdown
+	stats    SessionStats              // The outcome of the shutdown
+	mx       sync.Mutex                // Mutex for thread safety
+}
+
+// sessionEntry identifies a tracked session, as sessions are not necessarily comparable.
+type sessionEntry struct {
+	once sync.Once // Makes sure the session is untracked once
+}
+
+// NewSessionRegistry creates a new SessionRegistry with the given grace period and termination notice.
+// If the notice is empty, DefaultSessionNotice is sent.
+func NewSessionRegistry(grace time.Duration, notice string) *SessionRegistry {
+	if notice == "" {
+		notice = DefaultSessionNotice
+	}
+
+	return &SessionRegistry{grace: grace, notice: notice, sessions: make(map[*sessionEntry]Session)}
+}
+
+// Track registers the session and returns the function to call once the session ends.
+// It returns ErrSessionsClosed if the registry is shutting down; the caller should then
+// refuse the session.
+func (r *SessionRegistry) Track(s Session) (func(), error) {
+	r.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer r.mx.Unlock() // Release the lock after the function finishes.
+
+	if r.closing {
+		return nil, ErrSessionsClosed
+	}
+
+	entry := &sessionEntry{}
+	r.sessions[entry] = s
+
+	return func() { entry.once.Do(func() { r.untrack(entry) }) }, nil
+}
+
+// Len returns the number of tracked sessions.
+func (r *SessionRegistry) Len() int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return len(r.sessions)
+}
+
+// Stats returns the outcome of closing the registry.
+func (r *SessionRegistry) Stats() SessionStats {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return r.stats
+}
+
+// untrack removes the session from the registry.
+func (r *SessionRegistry) untrack(entry *sessionEntry) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, ok := r.sessions[entry]; !ok {
+		return
+	}
+
+	delete(r.sessions, entry)
+
+	if r.closing {
+		r.stats.Disconnected++
+	}
+
+	if len(r.sessions) == 0 && r.empty != nil {
+		close(r.empty)
+		r.empty = nil
+	}
+}
+
+// CloseContext sends the termination notice to the tracked sessions, waits until the users
+// disconnect, the grace period passes, or the context is done, and then terminates the remaining sessions.
+// The sessions are notified and terminated concurrently; once the context is done, the pending
+// notifications and terminations are not waited for, and the context error is returned.
+func (r *SessionRegistry) CloseContext(ctx context.Context) error {
+	r.mx.Lock()
+	r.closing = true
+
+	sessions := make([]Session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		sessions = append(sessions, s)
+	}
+
+	r.mx.Unlock()
+
+	// Notify without holding the lock, as the sessions may end right away.
+	errs := eachSession(ctx, sessions, func(s Session) error {
+		if err := s.Notify(r.notice); err != nil {
+			return err
+		}
+
+		r.mx.Lock()
+		r.stats.Notified++
+		r.mx.Unlock()
+
+		return nil
+	})
+
+	r.mx.Lock()
+	empty := make(chan struct{})
+	if len(r.sessions) == 0 {
+		close(empty)
+	} else {
+		r.empty = empty
+	}
+
+	r.mx.Unlock()
+
+	timer := time.NewTimer(r.grace) // Let the users disconnect
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out.
+	case <-timer.C: // The grace period has expired.
+	case <-empty: // All users have disconnected.
+		return errs
+	}
+
+	r.mx.Lock()
+	stragglers := make([]Session, 0, len(r.sessions))
+
+	for entry, s := range r.sessions {
+		stragglers = append(stragglers, s)
+		delete(r.sessions, entry)
+		r.stats.Terminated++
+	}
+
+	r.empty = nil
+	r.mx.Unlock()
+
+	// Terminate the stragglers without holding the lock, as they may untrack themselves.
+	return multierr.Append(errs, eachSession(ctx, stragglers, Session.Close))
+}
+
+// eachSession calls fn for the sessions concurrently and waits for the calls until the context is done.
+func eachSession(ctx context.Context, sessions []Session, fn func(Session) error) error {
+	results := make(chan error, len(sessions)) // Buffered, so the calls finishing late do not block
+
+	for _, s := range sessions {
+		go func(s Session) {
+			results <- fn(s)
+		}(s)
+	}
+
+	var errs error
+
+	for range sessions {
+		select {
+		case <-ctx.Done(): // If the context is cancelled or times out.
+			return multierr.Append(errs, ctx.Err())
+		case err := <-results:
+			errs = multierr.Append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// Close terminates the tracked sessions after the grace period.
+func (r *SessionRegistry) Close() error {
+	return r.CloseContext(context.Background())
+}
@@ -0,0 +1,163 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSession is a session recording the notices and disconnecting on notice if configured.
+type fakeSession struct {
+	mx         sync.Mutex
+	notices    []string
+	closed     bool
+	notifyErr  error
+	disconnect func() // Called on notice, if set
+}
+
+func (s *fakeSession) Notify(notice string) error {
+	s.mx.Lock()
+	s.notices = append(s.notices, notice)
+	disconnect := s.disconnect
+	s.mx.Unlock()
+
+	if disconnect != nil {
+		go disconnect()
+	}
+
+	return s.notifyErr
+}
+
+func (s *fakeSession) Close() error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.closed = true
+
+	return nil
+}
+
+func TestSessionRegistry(t *testing.T) {
+	registry := NewSessionRegistry(time.Second, "")
+
+	polite := &fakeSession{}
+	done, err := registry.Track(polite)
+	require.NoError(t, err)
+	polite.disconnect = done
+
+	stubborn := &fakeSession{}
+	_, err = registry.Track(stubborn)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, registry.Len())
+
+	registry.grace = 20 * time.Millisecond
+	assert.NoError(t, registry.Close())
+
+	assert.Equal(t, []string{DefaultSessionNotice}, polite.notices)
+	assert.False(t, polite.closed)
+	assert.Equal(t, []string{DefaultSessionNotice}, stubborn.notices)
+	assert.True(t, stubborn.closed)
+	assert.Zero(t, registry.Len())
+	assert.Equal(t, SessionStats{Notified: 2, Disconnected: 1, Terminated: 1}, registry.Stats())
+
+	_, err = registry.Track(&fakeSession{})
+	assert.ErrorIs(t, err, ErrSessionsClosed)
+}
+
+func TestSessionRegistry_AllDisconnected(t *testing.T) {
+	registry := NewSessionRegistry(time.Minute, "bye")
+
+	session := &fakeSession{}
+	done, err := registry.Track(session)
+	require.NoError(t, err)
+	session.disconnect = done
+
+	start := time.Now()
+	assert.NoError(t, registry.Close())
+	assert.Less(t, time.Since(start), time.Second)
+
+	assert.Equal(t, []string{"bye"}, session.notices)
+	assert.False(t, session.closed)
+	assert.Equal(t, SessionStats{Notified: 1, Disconnected: 1}, registry.Stats())
+
+	done() // Untracking twice is a no-op.
+	assert.Equal(t, SessionStats{Notified: 1, Disconnected: 1}, registry.Stats())
+}
+
+func TestSessionRegistry_Context(t *testing.T) {
+	registry := NewSessionRegistry(time.Minute, "")
+
+	errNotify := errors.New("broken pipe")
+	session := &fakeSession{notifyErr: errNotify}
+	_, err := registry.Track(session)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, registry.CloseContext(ctx), errNotify)
+	assert.Eventually(t, func() bool {
+		session.mx.Lock()
+		defer session.mx.Unlock()
+
+		return session.closed
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, SessionStats{Terminated: 1}, registry.Stats())
+}
+
+// untrackingSession untracks itself when closed, like a session ending its connection.
+type untrackingSession struct {
+	fakeSession
+	untrack func()
+}
+
+func (s *untrackingSession) Close() error {
+	s.untrack()
+	return s.fakeSession.Close()
+}
+
+func TestSessionRegistry_UntrackOnClose(t *testing.T) {
+	registry := NewSessionRegistry(10*time.Millisecond, "")
+
+	session := &untrackingSession{}
+	untrack, err := registry.Track(session)
+	require.NoError(t, err)
+	session.untrack = untrack
+
+	assert.NoError(t, registry.Close()) // Does not deadlock
+	assert.True(t, session.closed)
+	assert.Zero(t, registry.Len())
+	assert.Equal(t, SessionStats{Notified: 1, Terminated: 1}, registry.Stats())
+}
+
+// blockingSession blocks in Notify until released.
+type blockingSession struct {
+	fakeSession
+	release chan struct{}
+}
+
+func (s *blockingSession) Notify(string) error {
+	<-s.release
+	return nil
+}
+
+func TestSessionRegistry_NotifyContext(t *testing.T) {
+	registry := NewSessionRegistry(time.Minute, "")
+	session := &blockingSession{release: make(chan struct{})}
+	defer close(session.release)
+
+	_, err := registry.Track(session)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.ErrorIs(t, registry.CloseContext(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
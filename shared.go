@@ -0,0 +1,112 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrSharedClosed is returned by Shared.Acquire once the shared resource is closed.
+var ErrSharedClosed = errors.New("shutdown: shared resource closed")
+
+// Shared is a resource referenced by several components, such as one *sql.DB used by several modules,
+// closed exactly once: when the last reference is released, or when Shared itself is closed, whichever first.
+type Shared struct {
+	closer Closer     // The shared resource
+	refs   int        // Number of references not released yet
+	closed bool       // Whether the resource is closed
+	mx     sync.Mutex // Mutex for thread safety
+}
+
+// Share returns the shared resource. Each component appends its own reference instead of the resource:
+//
+//	db := shutdown.Share(sqlDB)
+//	ref, _ := db.Acquire()
+//	shutdown.Append(ref)
+//
+// Shared can also be appended itself, to close the resource at shutdown regardless of the references.
+func Share(closer Closer) *Shared {
+	return &Shared{closer: closer}
+}
+
+// Acquire returns a new reference to the resource, or ErrSharedClosed if the resource is closed.
+func (s *Shared) Acquire() (*Ref, error) {
+	s.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer s.mx.Unlock() // Release the lock after the function finishes.
+
+	if s.closed {
+		return nil, ErrSharedClosed
+	}
+
+	s.refs++
+
+	return &Ref{shared: s}, nil
+}
+
+// CloseContext closes the resource with the context unless it is already closed.
+func (s *Shared) CloseContext(ctx context.Context) error {
+	s.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer s.mx.Unlock() // Release the lock after the function finishes.
+
+	return s.close(ctx)
+}
+
+// Close closes the resource unless it is already closed.
+func (s *Shared) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// release drops a reference and closes the resource if it was the last one.
+func (s *Shared) release(ctx context.Context) error {
+	s.mx.Lock()         // Acquire the lock to ensure thread safety.
+	defer s.mx.Unlock() // Release the lock after the function finishes.
+
+	s.refs--
+	if s.refs > 0 {
+		return nil
+	}
+
+	return s.close(ctx)
+}
+
+// close closes the resource once. The caller must hold mx.
+func (s *Shared) close(ctx context.Context) error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	return closeCtx(ctx, s.closer)
+}
+
+// unwrap returns the shared resource.
+func (s *Shared) unwrap() Closer {
+	return s.closer
+}
+
+// Ref is a reference to a Shared resource.
+type Ref struct {
+	shared *Shared   // The referenced resource
+	once   sync.Once // Ensures the reference is released only once
+}
+
+// CloseContext releases the reference, closing the resource with the context if it was the last one.
+// Releasing the reference again is a no-op.
+func (r *Ref) CloseContext(ctx context.Context) (err error) {
+	r.once.Do(func() {
+		err = r.shared.release(ctx)
+	})
+
+	return err
+}
+
+// Close releases the reference, closing the resource if it was the last one.
+func (r *Ref) Close() error {
+	return r.CloseContext(context.Background())
+}
+
+// Release is an alias of Close, for the components releasing the reference before the shutdown.
+func (r *Ref) Release() error {
+	return r.Close()
+}
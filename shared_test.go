@@ -0,0 +1,63 @@
+package shutdown
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShared(t *testing.T) {
+	var closes atomic.Int32
+
+	errClose := errors.New("close")
+	db := Share(Fn(func() error {
+		closes.Add(1)
+		return errClose
+	}))
+
+	lifo := &Lifo{}
+
+	for i := 0; i < 3; i++ {
+		ref, err := db.Acquire()
+		require.NoError(t, err)
+		lifo.Append(ref)
+	}
+
+	early, err := db.Acquire()
+	require.NoError(t, err)
+	assert.NoError(t, early.Release())
+	assert.NoError(t, early.Release(), "Expected a second release to be a no-op")
+	assert.Equal(t, int32(0), closes.Load())
+
+	assert.ErrorIs(t, lifo.Close(), errClose)
+	assert.Equal(t, int32(1), closes.Load())
+
+	_, err = db.Acquire()
+	assert.ErrorIs(t, err, ErrSharedClosed)
+	assert.NoError(t, db.Close())
+	assert.Equal(t, int32(1), closes.Load())
+}
+
+func TestShared_CloseAtShutdown(t *testing.T) {
+	var closes atomic.Int32
+
+	db := Share(Fn(func() error {
+		closes.Add(1)
+		return nil
+	}))
+
+	ref, err := db.Acquire()
+	require.NoError(t, err)
+
+	lifo := &Lifo{}
+	lifo.Append(db) // Closed at shutdown even though a reference is held
+
+	require.NoError(t, lifo.Close())
+	assert.Equal(t, int32(1), closes.Load())
+
+	assert.NoError(t, ref.Release())
+	assert.Equal(t, int32(1), closes.Load())
+}
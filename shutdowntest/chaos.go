@@ -0,0 +1,148 @@
+// Package shutdowntest provides utilities for testing shutdown configurations.
+package shutdowntest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/partyzanex/shutdown"
+)
+
+// ErrChaos is the error injected into closers by Chaos.
+var ErrChaos = errors.New("shutdowntest: injected close error")
+
+// Config configures the faults injected by Chaos.
+type Config struct {
+	Seed      int64         // Seed of the random source, the same seed reproduces the same faults
+	MaxDelay  time.Duration // Maximum random delay added before closing
+	ErrorRate float64       // Probability in [0, 1] of returning ErrChaos instead of closing
+	PanicRate float64       // Probability in [0, 1] of panicking instead of closing
+}
+
+// Chaos injects random delays, errors and panics into closers,
+// to validate that the shutdown configuration of an application is robust.
+// Faults are decided when a closer is wrapped, so the same seed and registration
+// order always produce the same faults.
+type Chaos struct {
+	cfg Config
+	rnd *rand.Rand // Seeded random source
+	mx  sync.Mutex // Mutex for thread safety of the random source
+}
+
+// NewChaos creates a new Chaos with the given configuration.
+func NewChaos(cfg Config) *Chaos {
+	return &Chaos{
+		cfg: cfg,
+		rnd: rand.New(rand.NewSource(cfg.Seed)), //nolint:gosec // reproducibility is required, not security
+	}
+}
+
+// fault is the fault decided for a single closer.
+type fault struct {
+	delay  time.Duration
+	fail   bool
+	panics bool
+}
+
+func (c *Chaos) next() fault {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	f := fault{
+		fail:   c.rnd.Float64() < c.cfg.ErrorRate,
+		panics: c.rnd.Float64() < c.cfg.PanicRate,
+	}
+
+	if c.cfg.MaxDelay > 0 {
+		f.delay = time.Duration(c.rnd.Int63n(int64(c.cfg.MaxDelay)))
+	}
+
+	return f
+}
+
+// chaosCloser injects a fault before closing the wrapped closer.
+type chaosCloser struct {
+	closer shutdown.Closer // The wrapped closer
+	fault  fault           // The fault decided when wrapping
+}
+
+// Wrap returns a closer that injects a random fault before closing the given closer.
+// The wrapper keeps the name of the closer and passes the context of the close to it,
+// and the closer is still recognized through the wrapper, see shutdown.Wrapper.
+func (c *Chaos) Wrap(closer shutdown.Closer) shutdown.Closer {
+	return &chaosCloser{closer: closer, fault: c.next()}
+}
+
+// Close injects the fault and closes the wrapped closer.
+func (c *chaosCloser) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+// CloseContext injects the fault and closes the wrapped closer with the context.
+// The delay is cut short if the context is done.
+func (c *chaosCloser) CloseContext(ctx context.Context) error {
+	if c.fault.delay > 0 {
+		timer := time.NewTimer(c.fault.delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if c.fault.panics {
+		panic(ErrChaos)
+	}
+
+	if c.fault.fail {
+		return ErrChaos
+	}
+
+	if cc, ok := c.closer.(shutdown.ContextCloser); ok {
+		return cc.CloseContext(ctx)
+	}
+
+	return c.closer.Close()
+}
+
+// Name returns the name of the wrapped closer, or its type if unnamed.
+func (c *chaosCloser) Name() string {
+	if n, ok := c.closer.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+
+	return fmt.Sprintf("%T", c.closer)
+}
+
+// Unwrap returns the wrapped closer.
+func (c *chaosCloser) Unwrap() shutdown.Closer {
+	return c.closer
+}
+
+// chaosClosure wraps every appended closer with Chaos.
+type chaosClosure struct {
+	shutdown.Closure        // The wrapped closure
+	chaos            *Chaos // The fault injector
+}
+
+// Closure returns a closure that wraps every appended closer with Chaos
+// before appending it to the given closure.
+func (c *Chaos) Closure(closure shutdown.Closure) shutdown.Closure {
+	return &chaosClosure{Closure: closure, chaos: c}
+}
+
+// Append wraps the closer with Chaos and appends it to the wrapped closure.
+func (c *chaosClosure) Append(closer shutdown.Closer) {
+	c.Closure.Append(c.chaos.Wrap(closer))
+}
+
+// WithContext associates the chaos closure with the given context.
+func (c *chaosClosure) WithContext(ctx context.Context) context.Context {
+	return shutdown.ClosureToContext(ctx, c)
+}
@@ -0,0 +1,98 @@
+package shutdowntest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/partyzanex/shutdown"
+)
+
+func outcomes(seed int64) []error {
+	chaos := NewChaos(Config{Seed: seed, ErrorRate: 0.5})
+	result := make([]error, 0, 10)
+
+	for i := 0; i < 10; i++ {
+		result = append(result, chaos.Wrap(shutdown.Fn(func() error { return nil })).Close())
+	}
+
+	return result
+}
+
+func TestChaos_Reproducible(t *testing.T) {
+	first := outcomes(42)
+	assert.Equal(t, first, outcomes(42))
+
+	failures := 0
+
+	for _, err := range first {
+		if errors.Is(err, ErrChaos) {
+			failures++
+		}
+	}
+
+	assert.Greater(t, failures, 0)
+	assert.Less(t, failures, len(first))
+}
+
+func TestChaos_Panic(t *testing.T) {
+	chaos := NewChaos(Config{Seed: 1, PanicRate: 1})
+	closer := chaos.Wrap(shutdown.Fn(func() error { return nil }))
+
+	assert.PanicsWithValue(t, ErrChaos, func() { _ = closer.Close() })
+}
+
+func TestChaos_Closure(t *testing.T) {
+	chaos := NewChaos(Config{Seed: 1, ErrorRate: 1, MaxDelay: 10 * time.Millisecond})
+	closure := chaos.Closure(&shutdown.Fifo{})
+
+	closed := false
+	closure.Append(shutdown.Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	assert.ErrorIs(t, closure.Close(), ErrChaos)
+	assert.False(t, closed)
+}
+
+func TestChaos_Wrap(t *testing.T) {
+	chaos := NewChaos(Config{Seed: 1, MaxDelay: time.Hour})
+
+	var got context.Context
+
+	inner := shutdown.Named("db", ctxCloser(func(ctx context.Context) error {
+		got = ctx
+		return nil
+	}))
+	closer := chaos.Wrap(inner)
+
+	assert.Equal(t, "db", closer.(interface{ Name() string }).Name())
+	assert.Equal(t, inner, closer.(shutdown.Wrapper).Unwrap())
+
+	// The delay is cut short by the context.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.ErrorIs(t, closer.(shutdown.ContextCloser).CloseContext(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	closer = NewChaos(Config{Seed: 1}).Wrap(inner)
+	ctx = context.WithValue(context.Background(), ctxKey{}, "value")
+	assert.NoError(t, closer.(shutdown.ContextCloser).CloseContext(ctx))
+	assert.Equal(t, "value", got.Value(ctxKey{}))
+}
+
+// ctxKey is the context key of the test values.
+type ctxKey struct{}
+
+// ctxCloser adapts a function to the ContextCloser interface.
+type ctxCloser func(ctx context.Context) error
+
+func (f ctxCloser) Close() error { return f(context.Background()) }
+
+func (f ctxCloser) CloseContext(ctx context.Context) error { return f(ctx) }
@@ -0,0 +1,40 @@
+package shutdowntest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/partyzanex/shutdown"
+)
+
+// DefaultCleanupTimeout is the deadline given to the closure by BindTestCleanup.
+const DefaultCleanupTimeout = 10 * time.Second
+
+// cleanupMargin is kept before the deadline of the test binary, so a hung closer fails
+// the test with its close error instead of the whole binary timing out.
+const cleanupMargin = time.Second
+
+// BindTestCleanup registers the close of the closure with tb.Cleanup, so integration tests
+// exercise the same teardown path as production. The closure is closed within DefaultCleanupTimeout,
+// or less if the test binary would time out first, and the test fails on close errors.
+func BindTestCleanup(tb testing.TB, c shutdown.Closure) {
+	tb.Helper()
+
+	tb.Cleanup(func() {
+		timeout := DefaultCleanupTimeout
+
+		if t, ok := tb.(interface{ Deadline() (time.Time, bool) }); ok {
+			if deadline, ok := t.Deadline(); ok && time.Until(deadline)-cleanupMargin < timeout {
+				timeout = time.Until(deadline) - cleanupMargin
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := c.CloseContext(ctx); err != nil {
+			tb.Errorf("shutdowntest: close: %v", err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package shutdowntest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/partyzanex/shutdown"
+)
+
+// fakeTB records the cleanups and the errors instead of failing the test.
+type fakeTB struct {
+	testing.TB
+
+	cleanups []func()
+	errors   []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestBindTestCleanup(t *testing.T) {
+	tb := &fakeTB{}
+	closed := false
+
+	lifo := &shutdown.Lifo{}
+	lifo.Append(shutdown.Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	BindTestCleanup(tb, lifo)
+	assert.False(t, closed, "Expected the closure to be closed on cleanup only")
+
+	for _, cleanup := range tb.cleanups {
+		cleanup()
+	}
+
+	assert.True(t, closed)
+	assert.Empty(t, tb.errors)
+}
+
+func TestBindTestCleanup_Error(t *testing.T) {
+	tb := &fakeTB{}
+
+	lifo := &shutdown.Lifo{}
+	lifo.Append(shutdown.Fn(func() error { return errors.New("pool busy") }))
+
+	BindTestCleanup(tb, lifo)
+	assert.Len(t, tb.cleanups, 1)
+	assert.Empty(t, tb.errors)
+
+	tb.cleanups[0]()
+	assert.Equal(t, []string{"shutdowntest: close: pool busy"}, tb.errors)
+}
@@ -0,0 +1,18 @@
+module github.com/partyzanex/shutdown/shutdownwire
+
+go 1.20
+
+require (
+	github.com/google/wire v0.5.0
+	github.com/partyzanex/shutdown v0.0.0
+	github.com/stretchr/testify v1.7.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/partyzanex/shutdown => ../
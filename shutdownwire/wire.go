@@ -0,0 +1,53 @@
+// Package shutdownwire provides the closures of the shutdown package to google/wire graphs.
+// It is a separate module, so the core module does not depend on wire.
+package shutdownwire
+
+import (
+	"github.com/google/wire"
+
+	"github.com/partyzanex/shutdown"
+)
+
+// ProviderSet provides the Closure of the graph, registered in the global closure,
+// its Appender view for the components that should only register closers,
+// and the Closure2 manager appending closers described by options to it.
+var ProviderSet = wire.NewSet(ProvideClosure, ProvideAppender, ProvideManager)
+
+// ProvideClosure returns the closure of the providers of the graph. It is registered in the global
+// closure, so the closers appended by the providers are closed by the package-level shutdown,
+// in the reverse order of their construction.
+func ProvideClosure() shutdown.Closure {
+	closure := &shutdown.Lifo{}
+	shutdown.Append(shutdown.Named("wire", closure))
+
+	return closure
+}
+
+// ProvideAppender returns the Appender view of the closure, see shutdown.ReadOnly.
+func ProvideAppender(closure shutdown.Closure) shutdown.Appender {
+	return shutdown.ReadOnly(closure)
+}
+
+// ProvideManager returns the Closure2 appending to the closure the closers described by options,
+// e.g. named and bounded by a timeout.
+func ProvideManager(closure shutdown.Closure) shutdown.Closure2 {
+	return shutdown.Upgrade(closure)
+}
+
+// Register appends the cleanup function returned by a wire injector to the global closure,
+// and returns the built value, matching the signature of the injectors:
+//
+//	app, err := shutdownwire.Register(initializeApp())
+//
+// If the injector failed, the cleanup function, nil by the wire convention, is not appended.
+func Register[T any](value T, cleanup func(), err error) (T, error) {
+	if err != nil {
+		return value, err
+	}
+
+	if cleanup != nil {
+		shutdown.AppendCleanup(cleanup)
+	}
+
+	return value, nil
+}
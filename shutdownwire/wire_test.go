@@ -0,0 +1,40 @@
+package shutdownwire
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/partyzanex/shutdown"
+)
+
+func TestProviderSet(t *testing.T) {
+	shutdown.SetPackageClosure(&shutdown.Lifo{})
+
+	closure := ProvideClosure()
+	manager := ProvideManager(closure)
+
+	var order []string
+
+	ProvideAppender(closure).Append(shutdown.Fn(func() error {
+		order = append(order, "db")
+		return nil
+	}))
+	manager.Append(shutdown.Fn(func() error {
+		order = append(order, "server")
+		return nil
+	}), shutdown.WithName("server"))
+
+	app, err := Register("app", func() { order = append(order, "cleanup") }, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "app", app)
+
+	expectedErr := errors.New("injector failed")
+	_, err = Register("", func() { order = append(order, "failed") }, expectedErr)
+	assert.ErrorIs(t, err, expectedErr)
+
+	require.NoError(t, shutdown.Close())
+	assert.Equal(t, []string{"cleanup", "server", "db"}, order)
+}
@@ -0,0 +1,61 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// ErrListenerClosed is returned by SignalListener.Wait when the listener is closed.
+var ErrListenerClosed = errors.New("shutdown: signal listener closed")
+
+// SignalListener is a Trigger listening for signals until it is closed.
+// Unlike SignalTrigger, the signals are registered once, from ListenSignals until Close,
+// so a library embedding this package inside a larger application can cleanly uninstall
+// its signal handling by closing the listener, e.g. by appending it to a closure.
+type SignalListener struct {
+	c    chan os.Signal // Channel receiving the signals
+	done chan struct{}  // Closed when the listener is closed
+	once sync.Once      // Ensures that the listener is closed once
+}
+
+// ListenSignals starts listening for the given signals.
+// The signals received before Wait is called are not lost: the last one is buffered.
+func ListenSignals(sig ...os.Signal) *SignalListener {
+	l := &SignalListener{
+		c:    make(chan os.Signal, 1),
+		done: make(chan struct{}),
+	}
+
+	notifySignals(l.c, sig)
+
+	return l
+}
+
+// Wait blocks until a signal is received, the listener is closed or the context is done.
+// It returns the received signal as the cause, ErrListenerClosed or the context error.
+func (l *SignalListener) Wait(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-l.done:
+		return "", ErrListenerClosed
+	case s := <-l.c:
+		handleQuit(s)
+		return "signal " + s.String(), nil
+	}
+}
+
+// Close stops the notifications and wakes up the pending Wait calls.
+// Once no other channel is registered for a signal, the runtime restores its default disposition.
+// Close is idempotent and always returns nil.
+func (l *SignalListener) Close() error {
+	l.once.Do(func() {
+		signal.Stop(l.c)
+		close(l.done)
+	})
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package shutdown
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignalListener(t *testing.T) {
+	l := ListenSignals(syscall.SIGUSR1)
+	defer l.Close()
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cause, err := l.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "signal user defined signal 1", cause)
+}
+
+func TestSignalListener_Close(t *testing.T) {
+	l := ListenSignals(syscall.SIGUSR1)
+
+	lifo := &Lifo{}
+	lifo.Append(l)
+
+	errs := make(chan error, 1)
+
+	go func() {
+		_, err := l.Wait(context.Background())
+		errs <- err
+	}()
+
+	assert.NoError(t, lifo.Close())
+	assert.ErrorIs(t, <-errs, ErrListenerClosed)
+	assert.NoError(t, l.Close())
+
+	_, err := l.Wait(context.Background())
+	assert.ErrorIs(t, err, ErrListenerClosed)
+}
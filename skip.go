@@ -0,0 +1,73 @@
+package shutdown
+
+import "context"
+
+// skipCloser skips the wrapped closer when the predicate holds at shutdown time.
+type skipCloser struct {
+	skip   func() bool // The predicate evaluated at shutdown time
+	closer Closer      // The wrapped closer
+}
+
+// SkipIf returns a closer that does not close c if skip returns true at shutdown time,
+// e.g. to skip a cache flush when a feature flag says the deploy is a rollback.
+// Closers skipped within a Closure2 are marked as Skipped in the report.
+func SkipIf(skip func() bool, c Closer) Closer {
+	return &skipCloser{skip: skip, closer: c}
+}
+
+// Close closes the wrapped closer unless it is skipped.
+func (s *skipCloser) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext closes the wrapped closer with the context unless it is skipped.
+// The decision taken for the current close by decideSkip is reused, so the predicate is evaluated once.
+func (s *skipCloser) CloseContext(ctx context.Context) error {
+	skip, ok := ctx.Value(skipKey{}).(skipDecision)
+	if !ok || skip.closer != s {
+		skip.skip = s.skip()
+	}
+
+	if skip.skip {
+		return nil
+	}
+
+	return closeCtx(ctx, s.closer)
+}
+
+// skipKey is the context key of the skip decision taken for the current close.
+type skipKey struct{}
+
+// skipDecision is the outcome of the predicate of a SkipIf closer for the current close.
+type skipDecision struct {
+	closer *skipCloser // The closer the decision is taken for
+	skip   bool        // Whether the closer is skipped
+}
+
+// decideSkip evaluates the predicate of the SkipIf closer wrapped by c, found through wrappers such as Named,
+// WithTimeout or a Closure2 entry. The decision has a nil closer if c does not wrap a SkipIf closer.
+func decideSkip(c Closer) skipDecision {
+	for {
+		if s, ok := c.(*skipCloser); ok {
+			return skipDecision{closer: s, skip: s.skip()}
+		}
+
+		if _, ok := c.(lister); ok {
+			return skipDecision{} // The closers of a nested closure decide for themselves
+		}
+
+		var ok bool
+		if c, ok = unwrapCloser(c); !ok {
+			return skipDecision{}
+		}
+	}
+}
+
+// withSkipDecision returns the context carrying the decision, reused by the SkipIf closer when closed with it.
+func withSkipDecision(ctx context.Context, d skipDecision) context.Context {
+	if d.closer == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, skipKey{}, d)
+}
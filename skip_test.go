@@ -0,0 +1,100 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipIf(t *testing.T) {
+	rollback := true
+	closed := false
+
+	closer := SkipIf(func() bool { return rollback }, Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	assert.NoError(t, closer.Close())
+	assert.False(t, closed)
+
+	rollback = false
+	assert.NoError(t, closer.Close())
+	assert.True(t, closed)
+}
+
+func TestSkipIf_Report(t *testing.T) {
+	closure := Upgrade(&Fifo{})
+	closure.Append(SkipIf(func() bool { return true }, &mockCloser{}), WithName("cache-flush"))
+	closure.Append(&mockCloser{}, WithName("db"))
+
+	report, err := closure.CloseContext(context.Background())
+	require.NoError(t, err)
+
+	if assert.Len(t, report.Results, 2) {
+		assert.Equal(t, "cache-flush", report.Results[0].Name)
+		assert.True(t, report.Results[0].Skipped)
+		assert.False(t, report.Results[1].Skipped)
+	}
+}
+
+func TestSkipIf_Wrapped(t *testing.T) {
+	resetPackage(&Fifo{})
+
+	var evaluated int
+
+	closed := false
+	closer := &mockCloser{closeFunc: func() error {
+		closed = true
+		return nil
+	}}
+	Append(Named("cache-flush", SkipIf(func() bool {
+		evaluated++
+		return evaluated > 1 // Skipped only if evaluated again
+	}, closer)))
+
+	closure := Upgrade(&Fifo{})
+	closure.Append(Named("cache-flush", SkipIf(func() bool {
+		evaluated++
+		return true
+	}, &mockCloser{})), WithTimeout(time.Second))
+
+	report, err := closure.CloseContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, evaluated)
+
+	if assert.Len(t, report.Results, 1) {
+		assert.True(t, report.Results[0].Skipped)
+	}
+
+	evaluated = 0
+
+	defer SetNotifier(nil)
+
+	n := &recordNotifier{}
+	SetNotifier(n)
+
+	require.NoError(t, Close())
+	assert.Equal(t, 1, evaluated)
+	assert.True(t, closed)
+
+	if assert.Len(t, n.report.Results, 1) {
+		assert.Equal(t, "cache-flush", n.report.Results[0].Name)
+		assert.False(t, n.report.Results[0].Skipped)
+	}
+}
+
+// foreignWrapper wraps a closer as the closers of other packages do, see Wrapper.
+type foreignWrapper struct{ closer Closer }
+
+func (w foreignWrapper) Close() error { return w.closer.Close() }
+
+func (w foreignWrapper) Unwrap() Closer { return w.closer }
+
+func TestSkipIf_ForeignWrapper(t *testing.T) {
+	skip := decideSkip(foreignWrapper{closer: SkipIf(func() bool { return true }, &mockCloser{})})
+	assert.True(t, skip.skip)
+}
@@ -0,0 +1,119 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// maxSLOHistory is the maximum number of durations kept by the SLO closure.
+const maxSLOHistory = 100
+
+// SLO is a shutdown latency objective, e.g. "shutdown must finish in 20s, 99% of the time".
+type SLO struct {
+	Target    time.Duration // The duration a shutdown must finish in
+	Objective float64       // The fraction of shutdowns finishing within Target, 1 if zero
+}
+
+// SLOViolation describes a shutdown that exceeded the target while the objective is not attained.
+type SLOViolation struct {
+	SLO
+	Duration time.Duration // The duration of the violating shutdown
+	Attained float64       // The fraction of the recorded shutdowns finishing within Target
+	Samples  int           // The number of the recorded shutdowns
+}
+
+// Error describes the violation.
+func (v *SLOViolation) Error() string {
+	return fmt.Sprintf("shutdown: took %s, exceeding the target of %s; %.2f%% of %d shutdowns within target, objective %.2f%%",
+		v.Duration, v.Target, v.Attained*100, v.Samples, v.objective()*100)
+}
+
+func (s SLO) objective() float64 {
+	if s.Objective <= 0 {
+		return 1
+	}
+
+	return s.Objective
+}
+
+// ViolationNotifier is notified about the shutdown latency SLO violations.
+// WebhookNotifier and CommandNotifier implement it with the "slo_violated" event.
+type ViolationNotifier interface {
+	SLOViolated(ctx context.Context, v *SLOViolation) error
+}
+
+// sloClosure records the durations of the wrapped Closure and reports the SLO violations.
+type sloClosure struct {
+	decorator                   // The wrapped closure
+	slo       SLO               // The objective
+	store     DurationStore     // The sink of the recorded durations
+	notifier  ViolationNotifier // Notified about the violations
+}
+
+// WithSLO wraps the closure so that the duration of its close sequence is recorded to the store,
+// keeping the last 100 durations. When a shutdown exceeds the target and the fraction of the recorded
+// shutdowns within the target falls below the objective, the notifier is notified, giving platform teams
+// the data to tune grace periods. The notifier gets a context limited by DefaultNotifyTimeout, detached
+// from the shutdown context. The violation itself is not returned as an error.
+func WithSLO(closure Closure, slo SLO, store DurationStore, notifier ViolationNotifier) Closure {
+	return &sloClosure{decorator: decorator{closure}, slo: slo, store: store, notifier: notifier}
+}
+
+// CloseContext closes the wrapped closure, records the duration and reports a violation.
+func (s *sloClosure) CloseContext(ctx context.Context) error {
+	start := time.Now()
+	err := s.Closure.CloseContext(ctx)
+	elapsed := time.Since(start)
+
+	durations, recordErr := recordDuration(s.store, elapsed, maxSLOHistory)
+	if durations == nil {
+		return multierr.Append(err, recordErr) // Nothing to check the SLO against
+	}
+
+	err = multierr.Append(err, recordErr)
+
+	if v := s.check(elapsed, durations); v != nil && s.notifier != nil {
+		// The shutdown context is likely done after a violation, notify with a fresh one.
+		notifyCtx, cancel := context.WithTimeout(detachContext(ctx), DefaultNotifyTimeout)
+		defer cancel()
+
+		err = multierr.Append(err, s.notifier.SLOViolated(notifyCtx, v))
+	}
+
+	return err
+}
+
+// check returns the violation of the SLO, or nil.
+func (s *sloClosure) check(elapsed time.Duration, durations []time.Duration) *SLOViolation {
+	if elapsed <= s.slo.Target {
+		return nil
+	}
+
+	within := 0
+
+	for _, d := range durations {
+		if d <= s.slo.Target {
+			within++
+		}
+	}
+
+	attained := float64(within) / float64(len(durations))
+	if attained >= s.slo.objective() {
+		return nil
+	}
+
+	return &SLOViolation{SLO: s.slo, Duration: elapsed, Attained: attained, Samples: len(durations)}
+}
+
+// Close closes the wrapped closure without context support, records the duration and reports a violation.
+func (s *sloClosure) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// WithContext associates the SLO closure with the given context.
+func (s *sloClosure) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, s)
+}
@@ -0,0 +1,88 @@
+package shutdown
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordViolations records the reported SLO violations.
+type recordViolations []*SLOViolation
+
+func (r *recordViolations) SLOViolated(_ context.Context, v *SLOViolation) error {
+	*r = append(*r, v)
+	return nil
+}
+
+func TestWithSLO(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "slo"))
+	require.NoError(t, store.Save([]time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}))
+
+	var violations recordViolations
+
+	slo := SLO{Target: 10 * time.Millisecond, Objective: 0.7}
+
+	closeIn := func(d time.Duration) {
+		closure := WithSLO(&Lifo{}, slo, store, &violations)
+		closure.Append(Fn(func() error {
+			time.Sleep(d)
+			return nil
+		}))
+		require.NoError(t, closure.Close())
+	}
+
+	closeIn(20 * time.Millisecond) // 3 of 4 within the target, the objective is attained
+	assert.Empty(t, violations)
+
+	closeIn(20 * time.Millisecond) // 3 of 5 within the target
+	require.Len(t, violations, 1)
+	assert.Equal(t, 5, violations[0].Samples)
+	assert.InDelta(t, 0.6, violations[0].Attained, 0.001)
+	assert.GreaterOrEqual(t, violations[0].Duration, 20*time.Millisecond)
+	assert.Contains(t, violations[0].Error(), "objective 70.00%")
+
+	closeIn(0) // Within the target, never a violation
+	assert.Len(t, violations, 1)
+
+	durations, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, durations, 6)
+}
+
+func TestSLOViolation_Notifier(t *testing.T) {
+	n := &CommandNotifier{Name: "sh", Args: []string{"-c", `test "$SHUTDOWN_EVENT" = "slo_violated" && test -n "$SHUTDOWN_ERROR"`}}
+	assert.NoError(t, n.SLOViolated(context.Background(), &SLOViolation{SLO: SLO{Target: time.Second}, Duration: 2 * time.Second}))
+}
+
+// ctxViolations records the context error of the violation notifications.
+type ctxViolations struct {
+	ctxErr error
+	called bool
+}
+
+func (c *ctxViolations) SLOViolated(ctx context.Context, _ *SLOViolation) error {
+	c.called, c.ctxErr = true, ctx.Err()
+	return nil
+}
+
+func TestWithSLO_TimedOut(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "slo"))
+	notifier := &ctxViolations{}
+
+	closure := WithSLO(&Lifo{}, SLO{Target: time.Millisecond}, store, notifier)
+	closure.Append(Fn(func() error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, closure.CloseContext(ctx), context.DeadlineExceeded)
+	assert.True(t, notifier.called)
+	assert.NoError(t, notifier.ctxErr)
+}
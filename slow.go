@@ -0,0 +1,65 @@
+package shutdown
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultSlowInterval is the interval of the progress warnings of WarnSlow used for a non-positive interval.
+const DefaultSlowInterval = 5 * time.Second
+
+// slowCloser is a closer logging progress warnings while the wrapped closer is still closing.
+type slowCloser struct {
+	closer   Closer        // The wrapped closer
+	name     string        // The name of the closer in the warnings
+	logger   Logger        // Logger of the warnings
+	interval time.Duration // Interval of the warnings
+}
+
+// WarnSlow returns a closer that logs a progress warning every interval while c is still closing,
+// e.g. "Still closing kafka-producer, 12s elapsed", rather than staying silent until a timeout.
+// The warnings are prefixed with the run ID of the shutdown, see RunIDFromContext.
+// The interval also limits the rate of the warnings; a non-positive interval means DefaultSlowInterval.
+// The returned closer is named name, and passes the shutdown context to c if it is a ContextCloser.
+func WarnSlow(c Closer, name string, logger Logger, interval time.Duration) Closer {
+	if interval <= 0 {
+		interval = DefaultSlowInterval
+	}
+
+	return &slowCloser{closer: c, name: name, logger: logger, interval: interval}
+}
+
+// Name returns the name of the closer.
+func (s *slowCloser) Name() string {
+	return s.name
+}
+
+// CloseContext closes the wrapped closer with the context, logging the progress warnings.
+func (s *slowCloser) CloseContext(ctx context.Context) error {
+	start := time.Now()
+	done := make(chan struct{}) // Channel to signal that the closer has finished
+	logger := withRunID(ctx, s.logger)
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				logger.Msgf("Still closing %s, %s elapsed", s.name, time.Since(start).Round(time.Second))
+			}
+		}
+	}()
+
+	defer close(done)
+
+	return closeCtx(ctx, s.closer)
+}
+
+// Close closes the wrapped closer without a deadline, logging the progress warnings.
+func (s *slowCloser) Close() error {
+	return s.CloseContext(context.Background())
+}
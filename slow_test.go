@@ -0,0 +1,62 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarnSlow(t *testing.T) {
+	logger := &mockLogger{}
+	closer := WarnSlow(Fn(func() error {
+		time.Sleep(55 * time.Millisecond)
+		return nil
+	}), "kafka-producer", logger, 20*time.Millisecond)
+
+	assert.NoError(t, closer.Close())
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	if assert.NotEmpty(t, logger.messages) {
+		assert.Equal(t, "Still closing kafka-producer, 0s elapsed", logger.messages[0])
+	}
+}
+
+func TestWarnSlow_RunID(t *testing.T) {
+	logger := &mockLogger{}
+	closer := WarnSlow(Fn(func() error {
+		time.Sleep(35 * time.Millisecond)
+		return nil
+	}), "kafka-producer", logger, 20*time.Millisecond)
+
+	ctx := RunIDToContext(context.Background(), "run-1")
+	assert.NoError(t, closer.(ContextCloser).CloseContext(ctx))
+	assert.Equal(t, "[run run-1] Still closing kafka-producer, 0s elapsed", getLastLoggedMessage(logger))
+}
+
+func TestWarnSlow_Fast(t *testing.T) {
+	logger := &mockLogger{}
+
+	assert.NoError(t, WarnSlow(&mockCloser{}, "cache", logger, 20*time.Millisecond).Close())
+	time.Sleep(30 * time.Millisecond)
+	assert.Empty(t, getLastLoggedMessage(logger))
+}
+
+type slowKey struct{}
+
+func TestWarnSlow_Context(t *testing.T) {
+	var got context.Context
+
+	closer := WarnSlow(&ctxCloserFunc{fn: func(ctx context.Context) error {
+		got = ctx
+		return nil
+	}}, "kafka-producer", &mockLogger{}, 0)
+
+	ctx := context.WithValue(context.Background(), slowKey{}, "value")
+	assert.NoError(t, closeCtx(ctx, closer))
+	assert.Equal(t, "value", got.Value(slowKey{}))
+	assert.Equal(t, "kafka-producer", describe(closer, 0).Name)
+}
@@ -0,0 +1,169 @@
+package shutdown
+
+import "context"
+
+// DefaultReason is the shutdown reason passed to the ShutdownListener closers if the context carries none.
+const DefaultReason = "shutdown"
+
+// reasonKey is a private struct used as a unique key for storing
+// and retrieving the shutdown reason in the context.
+type reasonKey struct{}
+
+// ReasonToContext associates the reason of the shutdown with the context, e.g. "signal terminated".
+// CloseOnTrigger associates the cause of the trigger.
+func ReasonToContext(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// ReasonFromContext retrieves the reason of the shutdown associated with the context.
+func ReasonFromContext(ctx context.Context) (string, bool) {
+	reason, ok := ctx.Value(reasonKey{}).(string)
+	return reason, ok
+}
+
+// ShutdownListener is implemented by closers that want to know about the shutdown before any closer is closed,
+// e.g. to stop retrying or to shorten their timeouts while the other closers are still closing.
+type ShutdownListener interface {
+	NotifyShutdown(reason string) // Must not block, it is called before the close sequence starts
+}
+
+// lister is implemented by the closures able to list their closers without removing them.
+type lister interface {
+	list() []Closer // Returns the registered closers, in the order of appending
+}
+
+// unwrapper is implemented by the closers wrapping another closer.
+type unwrapper interface {
+	unwrap() Closer // Returns the wrapped closer
+}
+
+// Wrapper is implemented by the closers of other packages wrapping another closer, e.g. to inject faults.
+// The wrapped closer is then still recognized through the wrapper, e.g. as a SkipIf closer, a Preparer,
+// a ShutdownListener or the logger of the signal helpers.
+type Wrapper interface {
+	Unwrap() Closer // Returns the wrapped closer
+}
+
+// unwrapCloser returns the closer wrapped by c, reporting false if c is not a wrapper.
+func unwrapCloser(c Closer) (Closer, bool) {
+	switch u := c.(type) {
+	case unwrapper:
+		return u.unwrap(), true
+	case Wrapper:
+		return u.Unwrap(), true
+	default:
+		return nil, false
+	}
+}
+
+// notifyShutdown informs the closers of the closure implementing ShutdownListener, including the closers
+// of nested closures, each in its own goroutine so that none can delay the close sequence.
+func notifyShutdown(ctx context.Context, closure Closure) {
+	reason, ok := ReasonFromContext(ctx)
+	if !ok {
+		reason = DefaultReason
+	}
+
+	var visit func(closer Closer)
+
+	visit = func(closer Closer) {
+		for {
+			if l, ok := closer.(ShutdownListener); ok {
+				go l.NotifyShutdown(reason)
+			}
+
+			if l, ok := closer.(lister); ok {
+				for _, c := range l.list() {
+					visit(c)
+				}
+			}
+
+			var ok bool
+			if closer, ok = unwrapCloser(closer); !ok {
+				return
+			}
+		}
+	}
+
+	visit(closure)
+}
+
+// list returns the closers of the Lifo stack, in the order of appending.
+func (l *Lifo) list() []Closer {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	return append([]Closer(nil), l.stack...)
+}
+
+// list returns the closers of the Fifo queue, in the order of appending.
+func (f *Fifo) list() []Closer {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	return append([]Closer(nil), f.queue...)
+}
+
+// list returns the closers of the Group, in the order of appending.
+func (g *Group) list() []Closer {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	closers := make([]Closer, 0, len(g.closers))
+	for _, h := range g.closers {
+		closers = append(closers, h.closer)
+	}
+
+	return closers
+}
+
+// unwrap returns the closer wrapped by the entry.
+func (e *entryCloser) unwrap() Closer {
+	return e.closer
+}
+
+// unwrap returns the named closer.
+func (n *namedCloser) unwrap() Closer {
+	return n.closer
+}
+
+// unwrap returns the closer of the trace region.
+func (c *tracedCloser) unwrap() Closer {
+	return c.closer
+}
+
+// unwrap returns the measured closer.
+func (c *measuredCloser) unwrap() Closer {
+	return c.closer
+}
+
+// unwrap returns the wrapped closure.
+func (u *upgraded) unwrap() Closer {
+	return u.closure
+}
+
+// list returns the closers of the Priority, in the order of appending.
+func (p *Priority) list() []Closer {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	closers := make([]Closer, 0, len(p.closers))
+	for _, c := range p.closers {
+		closers = append(closers, c.closer)
+	}
+
+	return closers
+}
+
+// list returns the closers of the Dag, in the order of appending.
+func (d *Dag) list() []Closer {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	closers := make([]Closer, 0, len(d.nodes))
+	for _, n := range d.nodes {
+		closers = append(closers, n.closer)
+	}
+
+	return closers
+}
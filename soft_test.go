@@ -0,0 +1,77 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listenerCloser sends the reason it is notified with to its channel.
+type listenerCloser struct {
+	reasons chan string
+}
+
+func (l *listenerCloser) NotifyShutdown(reason string) {
+	l.reasons <- reason
+}
+
+func (l *listenerCloser) Close() error {
+	return nil
+}
+
+func TestNotifyShutdown(t *testing.T) {
+	listener := &listenerCloser{reasons: make(chan string, 1)}
+	nested := &listenerCloser{reasons: make(chan string, 1)}
+
+	group := &Group{}
+	group.Append(Named("listener", listener))
+
+	lifo := &Lifo{}
+	lifo.Append(group)
+	lifo.Append(nested)
+
+	resetPackage(lifo)
+	defer resetPackage(&Lifo{})
+
+	require.NoError(t, CloseContext(ReasonToContext(context.Background(), "signal terminated")))
+
+	for _, reasons := range []chan string{listener.reasons, nested.reasons} {
+		select {
+		case reason := <-reasons:
+			assert.Equal(t, "signal terminated", reason)
+		case <-time.After(time.Second):
+			t.Fatal("the listener is not notified")
+		}
+	}
+}
+
+func TestNotifyShutdown_DefaultReason(t *testing.T) {
+	listener := &listenerCloser{reasons: make(chan string, 1)}
+
+	lifo := &Lifo{}
+	lifo.Append(listener)
+
+	resetPackage(lifo)
+	defer resetPackage(&Lifo{})
+
+	require.NoError(t, Close())
+
+	select {
+	case reason := <-listener.reasons:
+		assert.Equal(t, DefaultReason, reason)
+	case <-time.After(time.Second):
+		t.Fatal("the listener is not notified")
+	}
+}
+
+func TestReasonFromContext(t *testing.T) {
+	_, ok := ReasonFromContext(context.Background())
+	assert.False(t, ok)
+
+	reason, ok := ReasonFromContext(ReasonToContext(context.Background(), "deploy"))
+	assert.True(t, ok)
+	assert.Equal(t, "deploy", reason)
+}
@@ -0,0 +1,181 @@
+package shutdown
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Step describes how a single closer is closed, as part of a shutdown plan.
+type Step struct {
+	Name     string        // Name of the closer, or its type if it has no name
+	Phase    int           // Steps of the same phase are closed concurrently, phases in ascending order
+	Timeout  time.Duration // Own timeout of the closer, zero if it has none
+	Tags     []string      // Tags of the closer, see WithTags
+	Children []Step        // Steps of a composite closer, e.g. ParallelPhase or Sequence
+	Unnamed  bool          // Whether the name is derived from the type of the closer
+}
+
+// Planner is implemented by closures able to describe their close order.
+type Planner interface {
+	Plan() []Step // Returns the steps of the close sequence, ordered by phase
+}
+
+// describe returns the step of a single closer within the given phase.
+func describe(closer Closer, phase int) Step {
+	step := Step{Name: fmt.Sprintf("%T", closer), Phase: phase, Unnamed: true}
+
+	if e, ok := closer.(*entryCloser); ok {
+		step.Timeout = e.entry.Timeout
+		step.Tags = e.entry.Tags
+		closer = e.closer
+
+		if e.entry.Name != "" {
+			step.Name, step.Unnamed = e.entry.Name, false
+		} else {
+			step.Name = fmt.Sprintf("%T", closer)
+		}
+	}
+
+	if t, ok := closer.(*timedCloser); ok {
+		step.Timeout = t.timeout
+		closer = t.closer
+
+		if step.Unnamed {
+			step.Name = fmt.Sprintf("%T", closer)
+		}
+	}
+
+	if n, ok := closer.(interface{ Name() string }); ok {
+		step.Name, step.Unnamed = n.Name(), false
+	}
+
+	if p, ok := closer.(Planner); ok {
+		step.Children = p.Plan()
+	}
+
+	return step
+}
+
+// Plan returns the steps of the Lifo close sequence, the last appended closer first.
+func (l *Lifo) Plan() []Step {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	steps := make([]Step, 0, len(l.stack))
+
+	for i := len(l.stack) - 1; i >= 0; i-- {
+		steps = append(steps, describe(l.stack[i], len(steps)))
+	}
+
+	return steps
+}
+
+// Plan returns the steps of the Fifo close sequence, the first appended closer first.
+func (f *Fifo) Plan() []Step {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	steps := make([]Step, 0, len(f.queue))
+
+	for i, closer := range f.queue {
+		steps = append(steps, describe(closer, i))
+	}
+
+	return steps
+}
+
+// Plan returns the steps of the Group close sequence. Closers without ordering constraints
+// are in the first phase, closers appended with AppendAfter follow the phases of their constraints.
+func (g *Group) Plan() []Step {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	phases := make(map[*Handle]int, len(g.closers))
+	steps := make([]Step, 0, len(g.closers))
+
+	for _, h := range g.closers {
+		phase := 0
+
+		for _, after := range h.after {
+			if p, ok := phases[after]; ok && p+1 > phase {
+				phase = p + 1
+			}
+		}
+
+		phases[h] = phase
+		steps = append(steps, describe(h.closer, phase))
+	}
+
+	// Keep the order of appending within a phase.
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Phase < steps[j].Phase })
+
+	return steps
+}
+
+// Plan returns the steps of the upgraded closure, if it is a Planner.
+func (u *upgraded) Plan() []Step {
+	if p, ok := u.closure.(Planner); ok {
+		return p.Plan()
+	}
+
+	return nil
+}
+
+// Plan returns the steps of the downgraded closure, if it is a Planner.
+func (d *downgraded) Plan() []Step {
+	if p, ok := d.closure.(Planner); ok {
+		return p.Plan()
+	}
+
+	return nil
+}
+
+// Plan returns the steps of the Priority close sequence, one phase per priority, the highest first.
+func (p *Priority) Plan() []Step {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	steps := make([]Step, 0, len(p.closers))
+
+	for phase, level := range p.levels() {
+		for _, closer := range level {
+			steps = append(steps, describe(closer, phase))
+		}
+	}
+
+	return steps
+}
+
+// Plan returns the steps of the Dag close sequence. The closers no other closer depends on
+// are in the first phase, the others follow the phases of the closers depending on them.
+func (d *Dag) Plan() []Step {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	phases := make(map[*Node]int, len(d.nodes))
+
+	// Propagate the phases along the dependencies until they are stable; the graph is acyclic.
+	for changed := true; changed; {
+		changed = false
+
+		for _, n := range d.nodes {
+			for _, dep := range n.deps {
+				if phases[n]+1 > phases[dep] {
+					phases[dep] = phases[n] + 1
+					changed = true
+				}
+			}
+		}
+	}
+
+	steps := make([]Step, 0, len(d.nodes))
+	for _, n := range d.nodes {
+		steps = append(steps, describe(n.closer, phases[n]))
+	}
+
+	// Keep the order of appending within a phase.
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Phase < steps[j].Phase })
+
+	return steps
+}
@@ -0,0 +1,19 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFifo_Plan(t *testing.T) {
+	closure := Upgrade(&Fifo{})
+	closure.Append(&mockCloser{}, WithName("http"), WithTimeout(time.Second))
+	closure.Append(&mockCloser{})
+
+	assert.Equal(t, []Step{
+		{Name: "http", Phase: 0, Timeout: time.Second},
+		{Name: "*shutdown.mockCloser", Phase: 1, Unnamed: true},
+	}, closure.(Planner).Plan())
+}
@@ -0,0 +1,22 @@
+package shutdown
+
+import "io"
+
+// DefaultDrainLimit is the maximum number of bytes drained by AppendStream before closing.
+const DefaultDrainLimit = 256 << 10
+
+// Drain returns a closer that reads the stream to EOF, up to limit bytes, before closing it.
+// Closing an HTTP response body without draining it prevents the reuse of the connection.
+// Read errors are ignored, since the stream is closed anyway.
+func Drain(rc io.ReadCloser, limit int64) Closer {
+	return Fn(func() error {
+		_, _ = io.Copy(io.Discard, io.LimitReader(rc, limit))
+		return rc.Close()
+	})
+}
+
+// AppendStream appends a named stream to the global closure,
+// which is drained up to DefaultDrainLimit bytes before it is closed.
+func AppendStream(name string, rc io.ReadCloser) {
+	appendPackage(Named(name, Drain(rc, DefaultDrainLimit)))
+}
@@ -0,0 +1,40 @@
+package shutdown
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type trackedReader struct {
+	io.Reader
+	closed bool
+}
+
+func (r *trackedReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	reader := strings.NewReader("response body")
+	rc := &trackedReader{Reader: reader}
+
+	assert.NoError(t, Drain(rc, 8).Close())
+	assert.True(t, rc.closed)
+	assert.Equal(t, 5, reader.Len()) // Drained up to the limit only
+}
+
+func TestAppendStream(t *testing.T) {
+	resetPackage(&Lifo{})
+
+	reader := strings.NewReader("response body")
+	rc := &trackedReader{Reader: reader}
+	AppendStream("body", rc)
+
+	assert.NoError(t, Close())
+	assert.True(t, rc.closed)
+	assert.Zero(t, reader.Len())
+}
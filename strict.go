@@ -0,0 +1,97 @@
+package shutdown
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// StrictMode defines how unnamed or untimed closers appended to a Closure2 are treated.
+type StrictMode int
+
+const (
+	// StrictOff accepts any closer.
+	StrictOff StrictMode = iota
+	// StrictWarn logs a warning for every unnamed or untimed closer.
+	StrictWarn
+	// StrictPanic panics on every unnamed or untimed closer.
+	StrictPanic
+)
+
+// StrictEnv is the environment variable enabling the strict mode at startup: "warn" or "panic".
+const StrictEnv = "SHUTDOWN_STRICT"
+
+// stdLogger adapts the standard logger to the Logger interface.
+type stdLogger struct{}
+
+func (stdLogger) Msgf(format string, args ...interface{}) {
+	log.Printf(format, args...)
+}
+
+var (
+	strictMode              = strictModeFromEnv() // The current StrictMode
+	strictLogger Logger     = stdLogger{}         // Logger of the StrictWarn warnings
+	strictMx     sync.Mutex                       // Mutex for thread safety of the strict mode
+)
+
+// strictModeFromEnv returns the StrictMode configured by StrictEnv.
+func strictModeFromEnv() StrictMode {
+	switch os.Getenv(StrictEnv) {
+	case "warn":
+		return StrictWarn
+	case "panic":
+		return StrictPanic
+	default:
+		return StrictOff
+	}
+}
+
+// SetStrictMode sets the development-time strict mode, nudging large teams toward shutdown
+// configurations that are debuggable in production: closers appended to a Closure2 without
+// WithName or WithTimeout, or to the global closure with Append, TryAppend, AppendWithTimeout
+// or AppendFinal without being named or timed, are reported to the logger (StrictWarn)
+// or cause a panic (StrictPanic). The closers appended by the helpers of this package,
+// e.g. HTTPFramework, are not checked.
+// A nil logger keeps the current one, which logs through the standard log package by default.
+func SetStrictMode(mode StrictMode, logger Logger) {
+	strictMx.Lock()
+	defer strictMx.Unlock()
+
+	strictMode = mode
+
+	if logger != nil {
+		strictLogger = logger
+	}
+}
+
+// packageEntry describes the closer appended to the global closure by the given package, for checkStrict.
+func packageEntry(closer Closer, pkg string) Entry {
+	step := describe(closer, 0)
+	entry := Entry{Timeout: step.Timeout, Package: pkg}
+
+	if !step.Unnamed {
+		entry.Name = step.Name
+	}
+
+	return entry
+}
+
+// checkStrict reports the entry according to the strict mode if it is unnamed or untimed.
+func checkStrict(entry Entry) {
+	strictMx.Lock()
+	mode, logger := strictMode, strictLogger
+	strictMx.Unlock()
+
+	if mode == StrictOff || (entry.Name != "" && entry.Timeout > 0) {
+		return
+	}
+
+	msg := fmt.Sprintf("shutdown: closer %q appended by %s has no name or timeout", entry.Name, entry.Package)
+
+	if mode == StrictPanic {
+		panic(msg)
+	}
+
+	logger.Msgf("%s", msg)
+}
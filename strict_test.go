@@ -0,0 +1,64 @@
+package shutdown
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/partyzanex/shutdown/health"
+)
+
+func TestSetStrictMode(t *testing.T) {
+	defer SetStrictMode(StrictOff, stdLogger{})
+
+	logger := &mockLogger{}
+	SetStrictMode(StrictWarn, logger)
+
+	closure := Upgrade(&Lifo{})
+	closure.Append(&mockCloser{}, WithName("db"), WithTimeout(time.Second))
+	assert.Empty(t, getLastLoggedMessage(logger))
+
+	closure.Append(&mockCloser{}, WithName("cache"))
+	assert.Equal(t,
+		`shutdown: closer "cache" appended by github.com/partyzanex/shutdown has no name or timeout`,
+		getLastLoggedMessage(logger),
+	)
+
+	SetStrictMode(StrictPanic, nil)
+	assert.Panics(t, func() { closure.Append(&mockCloser{}) })
+}
+
+func TestStrictModeFromEnv(t *testing.T) {
+	t.Setenv(StrictEnv, "panic")
+	assert.Equal(t, StrictPanic, strictModeFromEnv())
+
+	t.Setenv(StrictEnv, "warn")
+	assert.Equal(t, StrictWarn, strictModeFromEnv())
+
+	t.Setenv(StrictEnv, "")
+	assert.Equal(t, StrictOff, strictModeFromEnv())
+}
+
+func TestSetStrictMode_Package(t *testing.T) {
+	defer SetStrictMode(StrictOff, stdLogger{})
+	resetPackage(&Lifo{})
+
+	logger := &mockLogger{}
+	SetStrictMode(StrictWarn, logger)
+
+	AppendWithTimeout(Named("db", &mockCloser{}), time.Second)
+	HTTPFramework(&http.Server{ReadHeaderTimeout: time.Second}, health.New(), 0, time.Second)
+	assert.Empty(t, getLastLoggedMessage(logger))
+
+	Append(Named("cache", &mockCloser{}))
+	assert.Equal(t,
+		`shutdown: closer "cache" appended by github.com/partyzanex/shutdown has no name or timeout`,
+		getLastLoggedMessage(logger),
+	)
+
+	SetStrictMode(StrictPanic, nil)
+	assert.Panics(t, func() { AppendFinal(&mockCloser{}) })
+	assert.Panics(t, func() { _ = TryAppend(&mockCloser{}) })
+}
@@ -0,0 +1,178 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StructTag is the struct tag guiding AppendStruct, e.g. `shutdown:"name=db,priority=10,timeout=5s"`.
+// The tag `shutdown:"-"` excludes the field.
+const StructTag = "shutdown"
+
+// Shutdowner is implemented by resources stopped with a context, such as *http.Server.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// shutdownerCloser adapts a Shutdowner to the Closer interface.
+type shutdownerCloser struct {
+	shutdowner Shutdowner // The wrapped resource
+}
+
+// CloseContext shuts the resource down with the given context.
+func (s shutdownerCloser) CloseContext(ctx context.Context) error {
+	return s.shutdowner.Shutdown(ctx)
+}
+
+// Close shuts the resource down without a deadline.
+func (s shutdownerCloser) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// AppendStruct appends the exported fields of the struct (or pointer to a struct) v implementing
+// Closer or Shutdowner to the global closure, in field order. It suits applications keeping all their
+// dependencies in one wiring struct. The closers are named after the fields, unless a name is given
+// in the struct tag. Nil fields and fields implementing neither interface are ignored.
+func AppendStruct(v any) error {
+	return appendStruct(v, func(closer Closer, opts ...AppendOption) {
+		var entry Entry
+
+		for _, opt := range opts {
+			opt(&entry)
+		}
+
+		appendPackage(Named(entry.Name, closer))
+	})
+}
+
+// AppendStructTo is like AppendStruct but appends the fields to the given Closure2,
+// which also honors the timeout, priority and tags options of the struct tag:
+//
+//	DB *sql.DB `shutdown:"name=db,timeout=5s,priority=10,tags=storage|critical"`
+func AppendStructTo(c Closure2, v any) error {
+	return appendStruct(v, c.Append)
+}
+
+// appendStruct walks the fields of v and appends their closers with add.
+func appendStruct(v any, add func(closer Closer, opts ...AppendOption)) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("shutdown: AppendStruct expects a struct, got %T", v)
+	}
+
+	type field struct {
+		closer Closer
+		opts   []AppendOption
+	}
+
+	fields := make([]field, 0, value.NumField())
+
+	for i := 0; i < value.NumField(); i++ {
+		sf := value.Type().Field(i)
+
+		tag, tagged := sf.Tag.Lookup(StructTag)
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+
+		closer, ok := fieldCloser(value.Field(i))
+		if !ok {
+			if tagged {
+				return fmt.Errorf("shutdown: field %s is tagged but is neither a Closer nor a Shutdowner", sf.Name)
+			}
+
+			continue
+		}
+
+		opts, err := parseStructTag(sf.Name, tag)
+		if err != nil {
+			return err
+		}
+
+		fields = append(fields, field{closer: closer, opts: opts})
+	}
+
+	// Append only once the whole struct is valid.
+	for _, f := range fields {
+		add(f.closer, f.opts...)
+	}
+
+	return nil
+}
+
+// fieldCloser returns the closer of the field, if the field (or its address) implements Closer or Shutdowner.
+func fieldCloser(v reflect.Value) (Closer, bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return nil, false
+		}
+	}
+
+	candidates := []reflect.Value{v}
+	if v.CanAddr() {
+		candidates = append(candidates, v.Addr()) // Methods with a pointer receiver on a value field
+	}
+
+	for _, c := range candidates {
+		switch resource := c.Interface().(type) {
+		case Closer:
+			return resource, true
+		case Shutdowner:
+			return shutdownerCloser{shutdowner: resource}, true
+		}
+	}
+
+	return nil, false
+}
+
+// parseStructTag parses the options of the struct tag of the named field.
+func parseStructTag(fieldName, tag string) ([]AppendOption, error) {
+	opts := []AppendOption{WithName(fieldName)}
+
+	for _, part := range strings.Split(tag, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("shutdown: invalid tag %q of field %s", tag, fieldName)
+		}
+
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "name":
+			opts = append(opts, WithName(value))
+		case "timeout":
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("shutdown: invalid timeout of field %s: %w", fieldName, err)
+			}
+
+			opts = append(opts, WithTimeout(d))
+		case "priority":
+			priority, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("shutdown: invalid priority of field %s: %w", fieldName, err)
+			}
+
+			opts = append(opts, WithPriority(priority))
+		case "tags":
+			opts = append(opts, WithTags(strings.Split(value, "|")...))
+		default:
+			return nil, fmt.Errorf("shutdown: unknown tag option %q of field %s", key, fieldName)
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,95 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordCloser records its name to the shared order when closed.
+type recordCloser struct {
+	name  string
+	order *[]string
+}
+
+func (r *recordCloser) Close() error {
+	*r.order = append(*r.order, r.name)
+	return nil
+}
+
+// recordShutdowner records its name to the shared order when shut down.
+type recordShutdowner struct {
+	name  string
+	order *[]string
+}
+
+func (r *recordShutdowner) Shutdown(context.Context) error {
+	*r.order = append(*r.order, r.name)
+	return nil
+}
+
+func TestAppendStruct(t *testing.T) {
+	var order []string
+
+	deps := struct {
+		DB      *recordCloser `shutdown:"name=database,timeout=1s,priority=10,tags=storage|critical"`
+		Server  *recordShutdowner
+		Cache   *recordCloser
+		Skipped *recordCloser `shutdown:"-"`
+		Config  string
+		private *recordCloser
+	}{
+		DB:      &recordCloser{name: "db", order: &order},
+		Server:  &recordShutdowner{name: "server", order: &order},
+		Skipped: &recordCloser{name: "skipped", order: &order},
+		private: &recordCloser{name: "private", order: &order},
+	}
+
+	fifo := &Fifo{}
+	closure := Upgrade(fifo)
+	require.NoError(t, AppendStructTo(closure, &deps))
+
+	report, err := closure.CloseContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "server"}, order)
+
+	if assert.Len(t, report.Results, 2) {
+		assert.Equal(t, "database", report.Results[0].Name)
+		assert.Equal(t, time.Second, report.Results[0].Timeout)
+		assert.Equal(t, 10, report.Results[0].Priority)
+		assert.Equal(t, []string{"storage", "critical"}, report.Results[0].Tags)
+		assert.Equal(t, "Server", report.Results[1].Name)
+	}
+}
+
+func TestAppendStruct_Global(t *testing.T) {
+	resetPackage(&Fifo{})
+
+	var order []string
+
+	require.NoError(t, AppendStruct(struct {
+		First  *recordCloser
+		Second *recordShutdowner
+	}{&recordCloser{name: "first", order: &order}, &recordShutdowner{name: "second", order: &order}}))
+
+	assert.NoError(t, Close())
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestAppendStruct_Invalid(t *testing.T) {
+	resetPackage(&Fifo{})
+
+	assert.Error(t, AppendStruct(42))
+	assert.Error(t, AppendStruct(struct {
+		Config string `shutdown:"name=config"`
+	}{}))
+	assert.Error(t, AppendStruct(struct {
+		DB *recordCloser `shutdown:"timeout=soon"`
+	}{DB: &recordCloser{}}))
+	assert.Error(t, AppendStruct(struct {
+		DB *recordCloser `shutdown:"unknown=1"`
+	}{DB: &recordCloser{}}))
+}
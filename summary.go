@@ -0,0 +1,96 @@
+package shutdown
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// untagged is the group of the failed closers without tags in the summary.
+const untagged = "untagged"
+
+// Summary formats the failures of the report grouped by the first tag of the closers,
+// with the number of errors per group and its most frequent error, so a shutdown with
+// 40 failures reads as "network: 38 errors (37× use of closed connection), storage: 2 errors"
+// instead of a wall of text. The most frequent error is shown only if it occurred more than once.
+// It returns an empty string if no closer failed.
+func (r *Report) Summary() string {
+	type group struct {
+		errors   int
+		messages map[string]int
+	}
+
+	groups := make(map[string]*group)
+
+	for _, res := range r.Failures() {
+		name := untagged
+		if len(res.Tags) > 0 {
+			name = res.Tags[0]
+		}
+
+		g, ok := groups[name]
+		if !ok {
+			g = &group{messages: make(map[string]int)}
+			groups[name] = g
+		}
+
+		g.errors++
+		g.messages[rootMessage(res.Err)]++
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+
+	for _, name := range names {
+		g := groups[name]
+
+		part := fmt.Sprintf("%s: %d error", name, g.errors)
+		if g.errors > 1 {
+			part += "s"
+		}
+
+		if msg, count := worstOffender(g.messages); count > 1 {
+			part += fmt.Sprintf(" (%d× %s)", count, msg)
+		}
+
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// rootMessage returns the message of the innermost wrapped error, without the prefixes
+// added by the wrappers such as Named, so that identical causes are counted together.
+func rootMessage(err error) string {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err.Error()
+		}
+
+		err = next
+	}
+}
+
+// worstOffender returns the most frequent message and its count; ties resolve to the smallest message.
+func worstOffender(messages map[string]int) (string, int) {
+	var (
+		worst string
+		count int
+	)
+
+	for msg, n := range messages {
+		if n > count || (n == count && msg < worst) {
+			worst, count = msg, n
+		}
+	}
+
+	return worst, count
+}
@@ -0,0 +1,34 @@
+package shutdown
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport_Summary(t *testing.T) {
+	report := &Report{}
+	assert.Empty(t, report.Summary())
+
+	for i := 0; i < 37; i++ {
+		report.Results = append(report.Results, Result{
+			Entry: Entry{Tags: []string{"network"}},
+			Err:   fmt.Errorf("conn-%d: %w", i, net.ErrClosed),
+		})
+	}
+
+	report.Results = append(report.Results,
+		Result{Entry: Entry{Tags: []string{"network"}}, Err: errors.New("connection reset")},
+		Result{Entry: Entry{Tags: []string{"storage", "critical"}}, Err: errors.New("disk full")},
+		Result{Entry: Entry{Tags: []string{"storage"}}, Err: errors.New("lock held")},
+		Result{Entry: Entry{Tags: []string{"storage"}}}, // Closed without error
+		Result{Err: errors.New("boom")},
+	)
+
+	assert.Equal(t,
+		"network: 38 errors (37× use of closed network connection), storage: 2 errors, untagged: 1 error",
+		report.Summary())
+}
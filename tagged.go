@@ -0,0 +1,104 @@
+package shutdown
+
+import (
+	"context"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// TagPolicy declares the strategy used for the closers with the given tag.
+type TagPolicy struct {
+	Tag string         // The tag of the closers
+	New func() Closure // Creates the strategy closing the tagged closers, e.g. func() Closure { return &Group{} }
+}
+
+// taggedGroup is the closure of the closers sharing a tag.
+type taggedGroup struct {
+	tag     string
+	closure Closure2
+}
+
+// Tagged is a Closure2 mixing strategies within a single registry: closers are grouped by tag,
+// each group is closed with the strategy of its TagPolicy, and the groups are closed one after
+// another in the order of the policies. Closers without a matching tag form the last group,
+// closed in Last-In-First-Out order.
+type Tagged struct {
+	groups   []taggedGroup // The groups of the policies, in order
+	fallback Closure2      // The group of the closers without a matching tag
+}
+
+// NewTagged creates a new Tagged closure with the given policies, e.g.
+//
+//	NewTagged(
+//		TagPolicy{Tag: "network", New: func() Closure { return &Group{} }},
+//		TagPolicy{Tag: "storage", New: func() Closure { return &Fifo{} }},
+//	)
+func NewTagged(policies ...TagPolicy) *Tagged {
+	t := &Tagged{fallback: Upgrade(&Lifo{})}
+
+	for _, p := range policies {
+		t.groups = append(t.groups, taggedGroup{tag: p.Tag, closure: Upgrade(p.New())})
+	}
+
+	return t
+}
+
+// Append appends the closer to the group of its first tag having a policy.
+func (t *Tagged) Append(closer Closer, opts ...AppendOption) {
+	entry := Entry{}
+
+	for _, opt := range opts {
+		opt(&entry)
+	}
+
+	t.group(entry.Tags).Append(closer, append(opts, withPackage(callerPackage(1)))...)
+}
+
+// group returns the closure of the group of the first tag having a policy.
+func (t *Tagged) group(tags []string) Closure2 {
+	for _, tag := range tags {
+		for _, g := range t.groups {
+			if g.tag == tag {
+				return g.closure
+			}
+		}
+	}
+
+	return t.fallback
+}
+
+// CloseContext closes the groups one after another, in the order of the policies,
+// and returns the merged report.
+func (t *Tagged) CloseContext(ctx context.Context) (*Report, error) {
+	return t.CloseExcept(ctx)
+}
+
+// CloseExcept closes the groups like CloseContext, except the closers having any of the given tags.
+func (t *Tagged) CloseExcept(ctx context.Context, tags ...string) (*Report, error) {
+	report := &Report{Started: time.Now()}
+	report.RunID, _ = RunIDFromContext(ctx)
+	report.Deadline, _ = DeadlineSourceFromContext(ctx)
+
+	var errs error
+
+	closures := make([]Closure2, 0, len(t.groups)+1)
+	for _, g := range t.groups {
+		closures = append(closures, g.closure)
+	}
+
+	for _, c := range append(closures, t.fallback) {
+		r, err := c.(PartialCloser).CloseExcept(ctx, tags...)
+		errs = multierr.Append(errs, err)
+		report.Results = append(report.Results, r.Results...)
+	}
+
+	report.Duration = time.Since(report.Started)
+
+	return report, errs
+}
+
+// withPackage overrides the package of the appended closer.
+func withPackage(pkg string) AppendOption {
+	return func(e *Entry) { e.Package = pkg }
+}
@@ -0,0 +1,49 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagged(t *testing.T) {
+	tagged := NewTagged(
+		TagPolicy{Tag: "network", New: func() Closure { return &Group{} }},
+		TagPolicy{Tag: "storage", New: func() Closure { return &Fifo{} }},
+	)
+
+	var (
+		mx    sync.Mutex
+		order []string
+	)
+
+	closer := func(name string, delay time.Duration) Closer {
+		return Fn(func() error {
+			time.Sleep(delay)
+			mx.Lock()
+			order = append(order, name)
+			mx.Unlock()
+			return nil
+		})
+	}
+
+	tagged.Append(closer("db", 0), WithName("db"), WithTags("storage"))
+	tagged.Append(closer("misc", 0), WithName("misc"))
+	tagged.Append(closer("http", 30*time.Millisecond), WithName("http"), WithTags("network"))
+	tagged.Append(closer("grpc", 30*time.Millisecond), WithName("grpc"), WithTags("network"))
+	tagged.Append(closer("cache", 0), WithName("cache"), WithTags("storage"))
+
+	start := time.Now()
+	report, err := tagged.CloseContext(context.Background())
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), 55*time.Millisecond) // The network group is closed concurrently
+	assert.ElementsMatch(t, []string{"http", "grpc"}, order[:2])
+	assert.Equal(t, []string{"db", "cache", "misc"}, order[2:])
+	assert.Len(t, report.Results, 5)
+	assert.Equal(t, "github.com/partyzanex/shutdown", report.Results[0].Package)
+}
@@ -0,0 +1,151 @@
+package shutdown
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/partyzanex/shutdown/health"
+)
+
+// ServiceConfig describes the components of a typical service wired by NewServiceTemplate.
+// All the fields are optional.
+type ServiceConfig struct {
+	Health     *health.Checker                   // Turned not-ready first, so load balancers stop routing traffic
+	DrainDelay time.Duration                     // Time given to the load balancers to notice the readiness change
+	Servers    []Shutdowner                      // Servers stopped concurrently, e.g. *http.Server
+	Workers    []Closer                          // Consumers and background jobs stopped in order after the servers
+	Stores     []Closer                          // Databases and caches closed in order after the workers, e.g. *sql.DB
+	Flushers   []func(ctx context.Context) error // Telemetry exporters flushed last, e.g. the OpenTelemetry providers
+	Timeout    time.Duration                     // Timeout of the whole shutdown, zero means no timeout
+	Signals    []os.Signal                       // Signals triggering the shutdown, SIGINT and SIGTERM if empty
+	Logger     Logger                            // Logs the received signal, required by Run
+}
+
+// Service closes the components of a service in the recommended order.
+type Service struct {
+	closure *Fifo         // The close sequence
+	timeout time.Duration // Timeout of the whole shutdown
+	signals []os.Signal   // Signals triggering the shutdown
+	logger  Logger        // Logs the received signal
+}
+
+// NewServiceTemplate wires the components of a typical service in their recommended order:
+// the readiness turns to failing, the load balancers are given the drain delay, the servers stop
+// accepting requests and finish the in-flight ones, the workers stop, the stores are closed,
+// and finally the telemetry is flushed, so the spans and metrics of the shutdown itself are exported.
+// It serves as a reference of the intended composition; use Append to add closers
+// between the workers and the stores.
+//
+//	svc := shutdown.NewServiceTemplate(shutdown.ServiceConfig{
+//		Health:   checker,
+//		Servers:  []shutdown.Shutdowner{httpServer},
+//		Stores:   []shutdown.Closer{db},
+//		Flushers: []func(context.Context) error{tracerProvider.Shutdown},
+//		Timeout:  30 * time.Second,
+//		Logger:   logger,
+//	})
+//	err := svc.Run(ctx)
+func NewServiceTemplate(cfg ServiceConfig) *Service {
+	svc := &Service{
+		closure: &Fifo{},
+		timeout: cfg.Timeout,
+		signals: cfg.Signals,
+		logger:  cfg.Logger,
+	}
+
+	if len(svc.signals) == 0 {
+		svc.signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	if cfg.Health != nil {
+		svc.closure.Append(Named("health", cfg.Health))
+	}
+
+	if cfg.DrainDelay > 0 {
+		svc.closure.Append(Named("drain delay", Fn(func() error {
+			time.Sleep(cfg.DrainDelay)
+			return nil
+		})))
+	}
+
+	if len(cfg.Servers) > 0 {
+		servers := make([]Closer, 0, len(cfg.Servers))
+		for _, srv := range cfg.Servers {
+			servers = append(servers, shutdownerCloser{shutdowner: srv})
+		}
+
+		svc.closure.Append(Named("servers", ParallelPhase(servers...)))
+	}
+
+	if len(cfg.Workers) > 0 {
+		svc.closure.Append(Named("workers", Sequence(cfg.Workers...)))
+	}
+
+	svc.closure.Append(Named("stores", Sequence(cfg.Stores...)))
+
+	flushers := make([]Closer, 0, len(cfg.Flushers))
+	for _, flush := range cfg.Flushers {
+		flushers = append(flushers, flusherCloser(flush))
+	}
+
+	svc.closure.Append(Named("telemetry", Sequence(flushers...)))
+
+	return svc
+}
+
+// Append adds a closer closed after the workers and before the stores.
+func (s *Service) Append(closer Closer) {
+	s.closure.mx.Lock()
+	defer s.closure.mx.Unlock()
+
+	i := len(s.closure.queue) - 2 // Before the stores and the telemetry
+	s.closure.queue = append(s.closure.queue[:i], append([]Closer{closer}, s.closure.queue[i:]...)...)
+	s.closure.added()
+}
+
+// Run blocks until one of the signals is received or the context is done,
+// then closes the service within the timeout.
+func (s *Service) Run(ctx context.Context) error {
+	WaitForSignalsContext(ctx, s.logger, s.signals...)
+
+	ctx = detachContext(ctx) // Keep the values, drop the cancellation of the trigger context
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	return s.CloseContext(ctx)
+}
+
+// CloseContext closes the components in the recommended order with context support.
+func (s *Service) CloseContext(ctx context.Context) error {
+	return s.closure.CloseContext(ctx)
+}
+
+// Close closes the components in the recommended order without a deadline.
+func (s *Service) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// Plan returns the steps of the close sequence.
+func (s *Service) Plan() []Step {
+	return s.closure.Plan()
+}
+
+// flusherCloser adapts a flush function to the Closer interface.
+type flusherCloser func(ctx context.Context) error
+
+// CloseContext flushes with the given context.
+func (f flusherCloser) CloseContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Close flushes without a deadline.
+func (f flusherCloser) Close() error {
+	return f(context.Background())
+}
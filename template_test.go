@@ -0,0 +1,80 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/partyzanex/shutdown/health"
+)
+
+// recordServer records its name to the shared order when shut down.
+type recordServer struct {
+	name  string
+	mx    *sync.Mutex
+	order *[]string
+}
+
+func (r *recordServer) Shutdown(context.Context) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	*r.order = append(*r.order, r.name)
+
+	return nil
+}
+
+func TestNewServiceTemplate(t *testing.T) {
+	var (
+		mx    sync.Mutex
+		order []string
+	)
+
+	record := func(name string) Closer {
+		return Fn(func() error {
+			mx.Lock()
+			defer mx.Unlock()
+
+			order = append(order, name)
+
+			return nil
+		})
+	}
+
+	checker := health.New()
+
+	svc := NewServiceTemplate(ServiceConfig{
+		Health:  checker,
+		Servers: []Shutdowner{&recordServer{name: "http", mx: &mx, order: &order}},
+		Workers: []Closer{record("consumer")},
+		Stores:  []Closer{record("db")},
+		Flushers: []func(context.Context) error{func(context.Context) error {
+			return record("otel").Close()
+		}},
+	})
+	svc.Append(record("cache warmer"))
+
+	require.NoError(t, svc.Close())
+	assert.ErrorIs(t, checker.Ready(), health.ErrShuttingDown)
+	assert.Equal(t, []string{"http", "consumer", "cache warmer", "db", "otel"}, order)
+}
+
+func TestService_Run(t *testing.T) {
+	flushErr := errors.New("exporter unavailable")
+
+	svc := NewServiceTemplate(ServiceConfig{
+		Flushers: []func(context.Context) error{func(context.Context) error { return flushErr }},
+		Timeout:  time.Second,
+		Logger:   &mockLogger{},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, svc.Run(ctx), flushErr)
+}
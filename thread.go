@@ -0,0 +1,129 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrExecutorClosed is returned when a function is run on a closed ThreadExecutor.
+var ErrExecutorClosed = errors.New("shutdown: executor closed")
+
+// ThreadExecutor runs functions one at a time on a dedicated goroutine locked to its OS thread,
+// for cgo libraries (GUI, GPU, audio) whose teardown must happen on the thread that initialized them.
+// Initialize the library with Run, and append its closers wrapped with Closer:
+//
+//	exec := shutdown.NewThreadExecutor()
+//	_ = exec.Run(func() error { return audio.Init() })
+//
+//	shutdown.Append(exec) // Stops the thread once the closers below are closed
+//	shutdown.Append(exec.Closer(shutdown.Fn(audio.Terminate)))
+//
+// The OS thread is terminated once the executor is closed, so no other goroutine inherits its thread-local state.
+type ThreadExecutor struct {
+	tasks chan func()   // The functions to run on the thread
+	done  chan struct{} // Closed to stop the thread
+	once  sync.Once     // Makes sure the thread is stopped once
+}
+
+// NewThreadExecutor starts a new ThreadExecutor.
+func NewThreadExecutor() *ThreadExecutor {
+	e := &ThreadExecutor{tasks: make(chan func()), done: make(chan struct{})}
+
+	go e.loop()
+
+	return e
+}
+
+// loop runs the functions on the locked OS thread until the executor is closed.
+// It returns without unlocking the thread, so the runtime terminates the thread.
+func (e *ThreadExecutor) loop() {
+	runtime.LockOSThread()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case task := <-e.tasks:
+			task()
+		}
+	}
+}
+
+// Run runs the function on the thread of the executor and returns its error.
+// It returns ErrExecutorClosed if the executor is closed.
+func (e *ThreadExecutor) Run(fn func() error) error {
+	return e.run(context.Background(), func(context.Context) error { return fn() })
+}
+
+// run runs the function with the context on the thread of the executor, and waits until it finishes
+// or the context is done. The function keeps running on the thread if the context is done first.
+func (e *ThreadExecutor) run(ctx context.Context, fn func(context.Context) error) error {
+	result := make(chan error, 1)
+
+	select {
+	case <-e.done:
+		return ErrExecutorClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	case e.tasks <- func() {
+		defer markGoroutine(ctx)() // A reentrant Close is recognized on the thread too
+
+		result <- fn(ctx)
+	}:
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-result:
+		return err
+	}
+}
+
+// Closer wraps the closer so that it is closed on the thread of the executor.
+// A panic of the closer is handled on the thread according to its PanicPolicy.
+func (e *ThreadExecutor) Closer(closer Closer) Closer {
+	return &threadCloser{closer: closer, executor: e}
+}
+
+// CloseContext stops the thread of the executor once the running function finishes.
+// The functions submitted afterwards fail with ErrExecutorClosed.
+func (e *ThreadExecutor) CloseContext(context.Context) error {
+	e.once.Do(func() { close(e.done) })
+	return nil
+}
+
+// Close stops the thread of the executor, see CloseContext.
+func (e *ThreadExecutor) Close() error {
+	return e.CloseContext(context.Background())
+}
+
+// threadCloser closes the wrapped closer on the thread of its executor.
+type threadCloser struct {
+	closer   Closer          // The wrapped closer
+	executor *ThreadExecutor // The executor of the thread
+}
+
+// Close closes the wrapped closer on the thread of the executor.
+func (c *threadCloser) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+// CloseContext closes the wrapped closer with the context on the thread of the executor.
+func (c *threadCloser) CloseContext(ctx context.Context) error {
+	return c.executor.run(ctx, func(ctx context.Context) error {
+		return closeWithPolicy(ctx, c.closer, policyFromContext(ctx))
+	})
+}
+
+// Name returns the name of the wrapped closer.
+func (c *threadCloser) Name() string {
+	return describe(c.closer, 0).Name
+}
+
+// unwrap returns the closer closed on the thread.
+func (c *threadCloser) unwrap() Closer {
+	return c.closer
+}
@@ -0,0 +1,32 @@
+package shutdown
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadExecutor_SameThread(t *testing.T) {
+	exec := NewThreadExecutor()
+	defer exec.Close()
+
+	var initTID, closeTID int
+
+	require.NoError(t, exec.Run(func() error {
+		initTID = syscall.Gettid()
+		return nil
+	}))
+
+	for i := 0; i < 10; i++ {
+		go func() { _ = exec.Run(func() error { return nil }) }()
+	}
+
+	require.NoError(t, exec.Closer(Fn(func() error {
+		closeTID = syscall.Gettid()
+		return nil
+	})).Close())
+
+	assert.Equal(t, initTID, closeTID)
+}
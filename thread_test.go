@@ -0,0 +1,75 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadExecutor(t *testing.T) {
+	exec := NewThreadExecutor()
+
+	initialized := false
+	require.NoError(t, exec.Run(func() error {
+		initialized = true
+		return nil
+	}))
+	assert.True(t, initialized)
+
+	errRun := errors.New("failed")
+	assert.ErrorIs(t, exec.Run(func() error { return errRun }), errRun)
+
+	closure := &Lifo{}
+	closure.Append(exec)
+
+	var order []string
+
+	closure.Append(exec.Closer(&recordCloser{name: "library", order: &order}))
+	closure.Append(exec.Closer(Named("panicking", Fn(func() error { panic("boom") }))))
+
+	var perr *PanicError
+	if assert.ErrorAs(t, closure.Close(), &perr) {
+		assert.Equal(t, "boom", perr.Value)
+	}
+
+	assert.Equal(t, []string{"library"}, order)
+	assert.ErrorIs(t, exec.Run(func() error { return nil }), ErrExecutorClosed)
+	assert.NoError(t, exec.Close())
+
+	steps := closure.Plan()
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, "panicking", steps[0].Name)
+	}
+}
+
+func TestThreadExecutor_Context(t *testing.T) {
+	exec := NewThreadExecutor()
+	defer exec.Close()
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		_ = exec.Run(func() error {
+			close(started)
+			<-release
+
+			return nil
+		})
+	}()
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// The thread is busy, so the closer does not start before the context is done.
+	closer := exec.Closer(Fn(func() error { return nil }))
+	assert.ErrorIs(t, closer.(ContextCloser).CloseContext(ctx), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, closer.Close())
+}
@@ -0,0 +1,53 @@
+package shutdown
+
+import (
+	"context"
+	"time"
+)
+
+// timedCloser bounds the close of the wrapped closer with its own timeout.
+type timedCloser struct {
+	closer  Closer        // The wrapped closer
+	timeout time.Duration // The maximum duration of the close
+}
+
+// Close closes the wrapped closer, giving up with ErrCloseTimeout once the timeout expires.
+func (t *timedCloser) Close() error {
+	return t.CloseContext(context.Background())
+}
+
+// CloseContext closes the wrapped closer with a context derived from ctx, giving up with ErrCloseTimeout
+// once the timeout expires, or with the context error if ctx is done first.
+// A closer ignoring the context keeps running in the background.
+func (t *timedCloser) CloseContext(ctx context.Context) error {
+	return closeWithTimeout(ctx, t.closer, t.timeout)
+}
+
+// unwrap returns the closer bounded by the timeout.
+func (t *timedCloser) unwrap() Closer {
+	return t.closer
+}
+
+// AppendWithTimeout pushes a new closer onto the Lifo stack, bounded by its own timeout,
+// so that a single hung resource does not consume the entire shutdown budget.
+// Once the timeout expires, an ErrCloseTimeout error is recorded and the next closer is closed.
+// Zero or negative timeout means no timeout.
+func (l *Lifo) AppendWithTimeout(closer Closer, timeout time.Duration) {
+	l.Append(&timedCloser{closer: closer, timeout: timeout})
+}
+
+// AppendWithTimeout adds a new closer to the Fifo queue, bounded by its own timeout,
+// so that a single hung resource does not consume the entire shutdown budget.
+// Once the timeout expires, an ErrCloseTimeout error is recorded and the next closer is closed.
+// Zero or negative timeout means no timeout.
+func (f *Fifo) AppendWithTimeout(closer Closer, timeout time.Duration) {
+	f.Append(&timedCloser{closer: closer, timeout: timeout})
+}
+
+// AppendWithTimeout appends a new closer to the global closure, bounded by its own timeout.
+func AppendWithTimeout(closer Closer, timeout time.Duration) {
+	closer = &timedCloser{closer: closer, timeout: timeout}
+
+	checkStrict(packageEntry(closer, callerPackage(1)))
+	appendPackage(closer)
+}
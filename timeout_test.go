@@ -0,0 +1,74 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	for name, closure := range map[string]interface {
+		Closure
+		AppendWithTimeout(closer Closer, timeout time.Duration)
+	}{"lifo": &Lifo{}, "fifo": &Fifo{}} {
+		var closed []string
+
+		closure.Append(Fn(func() error {
+			closed = append(closed, "first")
+			return nil
+		}))
+		closure.AppendWithTimeout(Fn(func() error {
+			<-release
+			return nil
+		}), 10*time.Millisecond)
+		closure.Append(Fn(func() error {
+			closed = append(closed, "last")
+			return nil
+		}))
+
+		err := closure.CloseContext(context.Background())
+		assert.ErrorIs(t, err, ErrCloseTimeout, name)
+		assert.ElementsMatch(t, []string{"first", "last"}, closed, name)
+	}
+}
+
+func TestAppendWithTimeout_Plan(t *testing.T) {
+	lifo := &Lifo{}
+	lifo.AppendWithTimeout(&mockCloser{}, time.Second)
+
+	assert.Equal(t, []Step{{Name: "*shutdown.mockCloser", Timeout: time.Second, Unnamed: true}}, lifo.Plan())
+}
+
+func TestAppendWithTimeout_Panic(t *testing.T) {
+	closure := &Fifo{}
+
+	closed := false
+
+	closure.AppendWithTimeout(Fn(func() error { panic("boom") }), time.Second)
+	closure.Append(Fn(func() error {
+		closed = true
+		return nil
+	}))
+
+	var perr *PanicError
+	if assert.ErrorAs(t, closure.Close(), &perr) {
+		assert.Equal(t, "boom", perr.Value)
+	}
+
+	assert.True(t, closed)
+
+	// The policy of the closer applies in the goroutine of the timed closer.
+	closure = &Fifo{}
+	closure.Append(WithPanicPolicy(PanicAbort, &timedCloser{closer: Fn(func() error { panic("boom") }), timeout: time.Second}))
+	closure.Append(Fn(func() error {
+		t.Error("Expected the close sequence to be aborted")
+		return nil
+	}))
+
+	assert.ErrorAs(t, closure.Close(), &perr)
+}
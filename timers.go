@@ -0,0 +1,126 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Timers is a registry of timers and tickers, which are stopped and drained when it is closed,
+// so that their callbacks do not fire mid-teardown and touch already closed resources.
+// Close it at the start of the shutdown, e.g. append it last to a Lifo.
+type Timers struct {
+	mx      sync.Mutex     // Mutex for thread safety of the fields below
+	timers  []*time.Timer  // The registered timers
+	tickers []*time.Ticker // The registered tickers
+	stopped bool           // Whether the registry is closed
+	running sync.WaitGroup // Counts the AfterFunc callbacks in progress
+}
+
+// AfterFunc is like time.AfterFunc, but the callback does not run once the registry is closed.
+func (t *Timers) AfterFunc(d time.Duration, f func()) *time.Timer {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	timer := time.AfterFunc(d, func() {
+		t.mx.Lock()
+		if t.stopped {
+			t.mx.Unlock()
+			return // Fired concurrently with Close
+		}
+
+		t.running.Add(1)
+		t.mx.Unlock()
+
+		defer t.running.Done()
+
+		f()
+	})
+
+	t.register(timer)
+
+	return timer
+}
+
+// NewTimer is like time.NewTimer, but the timer is stopped once the registry is closed.
+func (t *Timers) NewTimer(d time.Duration) *time.Timer {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	timer := time.NewTimer(d)
+	t.register(timer)
+
+	return timer
+}
+
+// NewTicker is like time.NewTicker, but the ticker is stopped once the registry is closed.
+func (t *Timers) NewTicker(d time.Duration) *time.Ticker {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	ticker := time.NewTicker(d)
+	if t.stopped {
+		ticker.Stop()
+	} else {
+		t.tickers = append(t.tickers, ticker)
+	}
+
+	return ticker
+}
+
+// register tracks the timer, or stops it right away if the registry is closed. The lock must be held.
+func (t *Timers) register(timer *time.Timer) {
+	if t.stopped {
+		stopTimer(timer)
+	} else {
+		t.timers = append(t.timers, timer)
+	}
+}
+
+// stopTimer stops the timer and drains its channel, so a pending tick is not received later.
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() && timer.C != nil {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
+
+// CloseContext stops and drains the registered timers and tickers, prevents the new ones from firing,
+// and waits for the AfterFunc callbacks in progress until the context is done.
+func (t *Timers) CloseContext(ctx context.Context) error {
+	t.mx.Lock()
+
+	t.stopped = true
+
+	for _, timer := range t.timers {
+		stopTimer(timer)
+	}
+
+	for _, ticker := range t.tickers {
+		ticker.Stop()
+	}
+
+	t.timers, t.tickers = nil, nil
+	t.mx.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		t.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
+// Close stops and drains the registered timers and tickers, and waits for the callbacks in progress.
+func (t *Timers) Close() error {
+	return t.CloseContext(context.Background())
+}
@@ -0,0 +1,65 @@
+package shutdown
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimers(t *testing.T) {
+	timers := &Timers{}
+
+	var fired atomic.Int32
+
+	timers.AfterFunc(20*time.Millisecond, func() { fired.Add(1) })
+	timer := timers.NewTimer(20 * time.Millisecond)
+	ticker := timers.NewTicker(5 * time.Millisecond)
+
+	assert.NoError(t, timers.Close())
+
+	time.Sleep(40 * time.Millisecond)
+	assert.Zero(t, fired.Load())
+
+	select {
+	case <-timer.C:
+		t.Fatal("the timer fired after Close")
+	case <-ticker.C:
+		t.Fatal("the ticker ticked after Close")
+	default:
+	}
+
+	// Registered after Close, never fire.
+	timers.AfterFunc(0, func() { fired.Add(1) })
+	late := timers.NewTimer(0)
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Zero(t, fired.Load())
+
+	select {
+	case <-late.C:
+		t.Fatal("the timer fired after Close")
+	default:
+	}
+}
+
+func TestTimers_Running(t *testing.T) {
+	timers := &Timers{}
+	started, release := make(chan struct{}), make(chan struct{})
+
+	timers.AfterFunc(0, func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, timers.CloseContext(ctx), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, timers.Close())
+}
@@ -0,0 +1,40 @@
+package shutdown
+
+import (
+	"bytes"
+	"context"
+	"runtime/trace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTrace(t *testing.T) {
+	closure := WithTrace(&Lifo{})
+
+	closed := false
+	closure.Append(Named("database", Fn(func() error {
+		closed = true
+		return nil
+	})))
+
+	var buf bytes.Buffer
+
+	require.NoError(t, trace.Start(&buf))
+	assert.NoError(t, closure.Close())
+	trace.Stop()
+
+	assert.True(t, closed)
+	assert.Contains(t, buf.String(), "shutdown")
+	assert.Contains(t, buf.String(), "database")
+
+	steps := closure.(*traceClosure).Closure.(*Lifo).Plan()
+	if assert.Len(t, steps, 1) {
+		assert.Equal(t, "database", steps[0].Name)
+	}
+
+	extracted, ok := ClosureFromContext(closure.WithContext(context.Background()))
+	assert.True(t, ok)
+	assert.Equal(t, closure, extracted)
+}
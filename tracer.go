@@ -0,0 +1,89 @@
+package shutdown
+
+import (
+	"context"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// Tracer starts the spans of a close sequence, see WithTracer. It is the subset of
+// the OpenTelemetry trace.Tracer used by the strategies, so this module does not depend
+// on OpenTelemetry; an OpenTelemetry tracer is adapted with a few lines:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, shutdown.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) End(err error) {
+//		if err != nil {
+//			s.RecordError(err)
+//			s.SetStatus(codes.Error, err.Error())
+//		}
+//		s.Span.End()
+//	}
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span) // Starts a span as a child of the span in ctx
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	End(err error) // Ends the span, recording the error if not nil
+}
+
+// SpanShutdown is the name of the parent span of a close sequence.
+const SpanShutdown = "shutdown"
+
+// WithTracer sets the Tracer of a strategy built with NewLifo, NewFifo or NewGroup.
+// CloseContext then runs in a "shutdown" span, and each closer is closed in a child span
+// named after the closer, as in Plan.
+func WithTracer(t Tracer) Option {
+	return func(o *options) error {
+		if err := o.applies("WithTracer", targetTraced); err != nil {
+			return err
+		}
+
+		o.tracer = t
+
+		return nil
+	}
+}
+
+// noopSpan is the span of a strategy without a tracer.
+type noopSpan struct{}
+
+// End does nothing.
+func (noopSpan) End(error) {}
+
+// startSpan starts a span with the tracer, if not nil.
+func startSpan(ctx context.Context, tracer Tracer, name string) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return tracer.Start(ctx, name)
+}
+
+// callTraced calls callClose in a span named after the closer, if the tracer is not nil.
+func callTraced(ctx context.Context, tracer Tracer, closer Closer, errs *error) bool {
+	if tracer == nil || isDeferred(closer) {
+		return callClose(ctx, closer, errs)
+	}
+
+	ctx, span := tracer.Start(ctx, describe(closer, 0).Name)
+
+	var err error
+
+	aborted := callClose(ctx, closer, &err)
+	span.End(err)
+
+	if err != nil {
+		*errs = multierr.Append(*errs, err)
+	}
+
+	return aborted
+}
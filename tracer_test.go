@@ -0,0 +1,72 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spanKey is the context key of the name of the current fake span.
+type spanKey struct{}
+
+// fakeTracer records the ended spans as "parent/name" paths with their errors.
+type fakeTracer struct {
+	mx    sync.Mutex
+	spans map[string]error
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	if parent, ok := ctx.Value(spanKey{}).(string); ok {
+		name = parent + "/" + name
+	}
+
+	return context.WithValue(ctx, spanKey{}, name), &fakeSpan{tracer: t, name: name}
+}
+
+type fakeSpan struct {
+	tracer *fakeTracer
+	name   string
+}
+
+func (s *fakeSpan) End(err error) {
+	s.tracer.mx.Lock()
+	defer s.tracer.mx.Unlock()
+	s.tracer.spans[s.name] = err
+}
+
+func TestWithTracer(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	newClosures := map[string]func(opts ...Option) (Closure, error){
+		"lifo":  func(opts ...Option) (Closure, error) { return NewLifo(opts...) },
+		"fifo":  func(opts ...Option) (Closure, error) { return NewFifo(opts...) },
+		"group": func(opts ...Option) (Closure, error) { return NewGroup(opts...) },
+	}
+
+	for name, newClosure := range newClosures {
+		t.Run(name, func(t *testing.T) {
+			tracer := &fakeTracer{spans: make(map[string]error)}
+
+			closure, err := newClosure(WithTracer(tracer))
+			require.NoError(t, err)
+
+			database := &ctxCloser{}
+			closure.Append(Named("database", database))
+			closure.Append(Named("cache", Fn(func() error { return errFailed })))
+
+			assert.ErrorIs(t, closure.Close(), errFailed)
+			require.NotNil(t, database.ctx)
+			assert.Equal(t, "shutdown/database", database.ctx.Value(spanKey{}))
+
+			if assert.Len(t, tracer.spans, 3) {
+				assert.ErrorIs(t, tracer.spans["shutdown"], errFailed)
+				assert.NoError(t, tracer.spans["shutdown/database"])
+				assert.ErrorIs(t, tracer.spans["shutdown/cache"], errFailed)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package shutdown
+
+import "context"
+
+// Tracked is a closure deriving the close order from the actual start order of the resources
+// at runtime, rather than from the registration order: the resources are started through
+// Start or Open and closed in the reverse order of their successful start.
+// Conditionally started resources are closed only if they were started,
+// and late starts are closed first.
+type Tracked struct {
+	Lifo
+}
+
+// Start runs the start function and, if it succeeds, tracks the returned closer under the given name.
+// A failed start is not tracked, so nothing is closed for it.
+func (t *Tracked) Start(name string, start func() (Closer, error)) error {
+	closer, err := start()
+	if err != nil {
+		return err
+	}
+
+	t.Append(Named(name, closer))
+
+	return nil
+}
+
+// Open is the generic form of Tracked.Start returning the opened resource:
+//
+//	db, err := shutdown.Open(tracked, "db", func() (*sql.DB, error) { return sql.Open("pgx", dsn) })
+func Open[T Closer](t *Tracked, name string, open func() (T, error)) (T, error) {
+	resource, err := open()
+	if err != nil {
+		return resource, err
+	}
+
+	t.Append(Named(name, resource))
+
+	return resource, nil
+}
+
+// WithContext associates the Tracked instance with the given context.
+func (t *Tracked) WithContext(ctx context.Context) context.Context {
+	return ClosureToContext(ctx, t)
+}
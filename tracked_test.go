@@ -0,0 +1,42 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracked(t *testing.T) {
+	var order []string
+
+	closer := func(name string) Closer {
+		return Fn(func() error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	tracked := &Tracked{}
+
+	startCache := func() (Closer, error) { return closer("cache"), nil }
+	startBroken := func() (Closer, error) { return nil, errors.New("broken") }
+
+	require.NoError(t, tracked.Start("db", func() (Closer, error) { return closer("db"), nil }))
+	assert.Error(t, tracked.Start("broken", startBroken))
+
+	fn, err := Open(tracked, "queue", func() (Fn, error) { return closer("queue").(Fn), nil })
+	require.NoError(t, err)
+	assert.NotNil(t, fn)
+
+	require.NoError(t, tracked.Start("cache", startCache)) // Started late, closed first
+
+	assert.NoError(t, tracked.Close())
+	assert.Equal(t, []string{"cache", "queue", "db"}, order)
+
+	extracted, ok := ClosureFromContext(tracked.WithContext(context.Background()))
+	assert.True(t, ok)
+	assert.Equal(t, tracked, extracted)
+}
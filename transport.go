@@ -0,0 +1,110 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// HTTPTransportCloser is an http.RoundTripper tracking the in-flight outbound requests,
+// so that outbound connection pools are drained on shutdown symmetrically to the inbound listeners.
+type HTTPTransportCloser struct {
+	rt       http.RoundTripper // The wrapped transport
+	inFlight InFlight          // Tracks the requests in progress
+	mx       sync.Mutex        // Mutex for thread safety of draining
+	draining bool              // Whether the requests are drained, the new ones are not tracked
+}
+
+// HTTPTransport wraps the transport, http.DefaultTransport if nil, to track the in-flight requests.
+// Use the result as the transport of the clients, and append it to the closure:
+//
+//	transport := shutdown.HTTPTransport(nil)
+//	client := &http.Client{Transport: transport}
+//	shutdown.Append(transport)
+//
+// A request is in flight until its response body is closed, or until it fails.
+// The response bodies must be closed: an unclosed body keeps its request in flight,
+// so Close blocks forever and CloseContext until its context is done.
+func HTTPTransport(rt http.RoundTripper) *HTTPTransportCloser {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return &HTTPTransportCloser{rt: rt}
+}
+
+// HTTPClient wraps the transport of the client with HTTPTransport and returns the wrapping transport,
+// to be appended to the closure:
+//
+//	shutdown.Append(shutdown.HTTPClient(http.DefaultClient))
+func HTTPClient(client *http.Client) *HTTPTransportCloser {
+	transport := HTTPTransport(client.Transport)
+	client.Transport = transport
+
+	return transport
+}
+
+// RoundTrip executes the request with the wrapped transport, tracking it until its response body is closed.
+// The requests starting once the transport is being drained are executed without tracking.
+func (h *HTTPTransportCloser) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.mx.Lock()
+	if h.draining {
+		h.mx.Unlock()
+		return h.rt.RoundTrip(req)
+	}
+
+	h.inFlight.Add()
+	h.mx.Unlock()
+
+	resp, err := h.rt.RoundTrip(req)
+	if err != nil {
+		h.inFlight.Done()
+		return nil, err
+	}
+
+	resp.Body = &trackedBody{ReadCloser: resp.Body, done: h.inFlight.Done}
+
+	return resp, nil
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport, if supported.
+func (h *HTTPTransportCloser) CloseIdleConnections() {
+	if c, ok := h.rt.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// CloseContext waits for the in-flight requests until the context is done,
+// and then closes the idle connections of the wrapped transport.
+// The requests starting after CloseContext is called are not waited for.
+func (h *HTTPTransportCloser) CloseContext(ctx context.Context) error {
+	h.mx.Lock()
+	h.draining = true
+	h.mx.Unlock()
+
+	err := h.inFlight.Wait(ctx)
+	h.CloseIdleConnections()
+
+	return err
+}
+
+// Close waits for the in-flight requests and closes the idle connections, see CloseContext.
+func (h *HTTPTransportCloser) Close() error {
+	return h.CloseContext(context.Background())
+}
+
+// trackedBody is a response body completing its in-flight request when closed.
+type trackedBody struct {
+	io.ReadCloser
+	done func()    // Completes the in-flight request
+	once sync.Once // Makes sure the request is completed once
+}
+
+// Close closes the body and completes the in-flight request.
+func (b *trackedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+
+	return err
+}
@@ -0,0 +1,98 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// idleTransport records the calls of CloseIdleConnections.
+type idleTransport struct {
+	http.RoundTripper
+	closedIdle bool
+}
+
+func (t *idleTransport) CloseIdleConnections() {
+	t.closedIdle = true
+}
+
+func TestHTTPTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	rt := &idleTransport{RoundTripper: http.DefaultTransport}
+	client := &http.Client{Transport: rt}
+	transport := HTTPClient(client)
+	assert.Equal(t, transport, client.Transport)
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+
+	closed := make(chan error, 1)
+	go func() { closed <- transport.Close() }()
+
+	select {
+	case <-closed:
+		t.Fatal("closed while the response body is open")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.NoError(t, resp.Body.Close())
+	assert.NoError(t, resp.Body.Close()) // Closing twice completes the request once
+
+	assert.NoError(t, <-closed)
+	assert.True(t, rt.closedIdle)
+}
+
+func TestHTTPTransport_Context(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	transport := HTTPTransport(nil)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, transport.CloseContext(ctx), context.DeadlineExceeded)
+
+	_, err = client.Get("http://127.0.0.1:0")
+	assert.Error(t, err) // A failed request is not in flight
+}
+
+func TestHTTPTransport_Draining(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	transport := HTTPTransport(nil)
+	client := &http.Client{Transport: transport}
+
+	assert.NoError(t, transport.Close())
+
+	// A request started once the transport is drained is not tracked,
+	// so its open body does not block closing again.
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.NoError(t, transport.Close())
+}
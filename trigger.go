@@ -0,0 +1,146 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// Trigger initiates a shutdown, e.g. on a signal or when a file appears.
+type Trigger interface {
+	// Wait blocks until the shutdown is triggered or the context is done.
+	// It returns the cause of the shutdown, or the context error.
+	Wait(ctx context.Context) (string, error)
+}
+
+// TriggerFunc is an adapter to allow the use of ordinary functions as triggers.
+type TriggerFunc func(ctx context.Context) (string, error)
+
+// Wait calls f(ctx).
+func (f TriggerFunc) Wait(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// SignalTrigger returns a trigger that fires when one of the given signals is received.
+func SignalTrigger(sig ...os.Signal) Trigger {
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		c := make(chan os.Signal, 1) // Channel to listen for signals.
+		notifySignals(c, sig)
+		defer signal.Stop(c)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case s := <-c:
+			handleQuit(s)
+			return "signal " + s.String(), nil
+		}
+	})
+}
+
+// DefaultPollInterval is the interval of FileTrigger used for a non-positive interval.
+const DefaultPollInterval = time.Second
+
+// FileTrigger returns a trigger that fires when the file at the given path appears.
+// The path is checked every interval, DefaultPollInterval if the interval is not positive.
+// Some orchestration and chaos-engineering tools communicate via files rather than signals,
+// e.g. /var/run/app/stop.
+func FileTrigger(path string, interval time.Duration) Trigger {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := os.Stat(path); err == nil {
+				return "file " + path, nil
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// CloseOnTrigger waits for the trigger to fire or until the context is done,
+// then closes the global closure. Like CloseOnSignalContext, the closing
+// is not bound to the cancellation of ctx. It is bound by the tighter of the default timeout
+// set with SetDefaultCloseTimeout and the deadline provided by the trigger, see DeadlineProvider.
+//
+// Parameters:
+// - ctx: The context that can be used to cancel or time out the waiting process.
+// - logger: An instance that implements the Logger interface, used for logging.
+// - trigger: The trigger to wait for.
+//
+// Returns:
+// - An error if encountered while waiting for the trigger or closing the global closure; otherwise, nil.
+func CloseOnTrigger(ctx context.Context, logger Logger, trigger Trigger) error {
+	cause, err := trigger.Wait(ctx)
+
+	switch {
+	case err == nil:
+		logger.Msgf("Shutdown triggered by %s", cause)
+	case ctx.Err() != nil:
+		cause = ctx.Err().Error()
+		logger.Msgf("Shutdown triggered by %s", cause)
+	default:
+		return err
+	}
+
+	useLogger(logger)
+
+	mu.Lock()
+	configured := pkgTimeout
+	mu.Unlock()
+
+	closeCtx := ReasonToContext(detachContext(ctx), cause)
+
+	// Adopt the tighter of the configured deadline and the one provided by the trigger.
+	if deadline, source := negotiateDeadline(configured, trigger); !deadline.IsZero() {
+		logger.Msgf("Shutdown deadline in %s, set by the %s", time.Until(deadline).Round(time.Millisecond), source)
+
+		var cancel context.CancelFunc
+
+		closeCtx, cancel = context.WithDeadline(context.WithValue(closeCtx, deadlineSourceKey{}, source), deadline)
+		defer cancel()
+	}
+
+	return CloseContext(closeCtx)
+}
+
+// processStart is the approximate start time of the process, used by LifetimeTrigger.
+var processStart = time.Now()
+
+// LifetimeTrigger returns a trigger that fires once the process has been running for maxUptime,
+// plus a random jitter in [0, jitter), so a fleet recycling its processes periodically
+// does not restart all instances at the same instant. See ScheduleTrigger to recycle them at scheduled times.
+func LifetimeTrigger(maxUptime, jitter time.Duration) Trigger {
+	deadline := processStart.Add(maxUptime)
+
+	if jitter > 0 {
+		deadline = deadline.Add(time.Duration(rand.Int63n(int64(jitter)))) //nolint:gosec // jitter does not need a secure random
+	}
+
+	return TriggerFunc(func(ctx context.Context) (string, error) {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timer.C:
+			return "max lifetime " + maxUptime.String(), nil
+		}
+	})
+}
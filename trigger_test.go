@@ -0,0 +1,90 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTrigger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stop")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = os.WriteFile(path, nil, 0o600)
+	}()
+
+	cause, err := FileTrigger(path, 5*time.Millisecond).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "file "+path, cause)
+}
+
+func TestFileTrigger_DefaultInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stop")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	cause, err := FileTrigger(path, 0).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "file "+path, cause)
+}
+
+func TestFileTrigger_Context(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := FileTrigger(filepath.Join(t.TempDir(), "stop"), 5*time.Millisecond).Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSignalTrigger(t *testing.T) {
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		process, _ := os.FindProcess(os.Getpid())
+		_ = process.Signal(os.Interrupt)
+	}()
+
+	cause, err := SignalTrigger(os.Interrupt).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "signal interrupt", cause)
+}
+
+func TestCloseOnTrigger(t *testing.T) {
+	resetPackage(&Lifo{})
+	logger := &mockLogger{}
+
+	mCloser := &pkgCloser{}
+	Append(mCloser)
+
+	trigger := TriggerFunc(func(context.Context) (string, error) { return "test", nil })
+
+	assert.NoError(t, CloseOnTrigger(context.Background(), logger, trigger))
+	assert.True(t, mCloser.isClose)
+	assert.Equal(t, "Shutdown triggered by test", getLastLoggedMessage(logger))
+}
+
+func TestCloseOnTrigger_Error(t *testing.T) {
+	expectedErr := errors.New("trigger error")
+	trigger := TriggerFunc(func(context.Context) (string, error) { return "", expectedErr })
+
+	assert.ErrorIs(t, CloseOnTrigger(context.Background(), &mockLogger{}, trigger), expectedErr)
+}
+
+func TestLifetimeTrigger(t *testing.T) {
+	uptime := time.Since(processStart)
+
+	cause, err := LifetimeTrigger(uptime+20*time.Millisecond, 10*time.Millisecond).Wait(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, cause, "max lifetime")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = LifetimeTrigger(time.Hour, 0).Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
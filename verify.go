@@ -0,0 +1,38 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// ErrUnclean is returned (wrapped) by the package-level CloseContext when a verification check fails.
+var ErrUnclean = errors.New("shutdown: unclean")
+
+var pkgVerifiers []func(ctx context.Context) error // Checks run after the global closure is closed
+
+// Verify registers a check run by the package-level CloseContext after all the closers of the global
+// closure finish and before the final closers, e.g. asserting that no connection to a host is left open
+// or that a queue is empty. A failed check marks the shutdown as unclean: its error is wrapped with ErrUnclean,
+// returned and reported to the notifier along with the close errors.
+// The checks get the context of the final closers.
+func Verify(check func(ctx context.Context) error) {
+	mu.Lock()         // Acquiring the lock
+	defer mu.Unlock() // Making sure to release the lock after the function exits
+	pkgVerifiers = append(pkgVerifiers, check)
+}
+
+// verify runs the registered checks in registration order. The caller must hold mu.
+func verify(ctx context.Context) error {
+	var errs error
+
+	for _, check := range pkgVerifiers {
+		if err := check(ctx); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%w: %w", ErrUnclean, err))
+		}
+	}
+
+	return errs
+}
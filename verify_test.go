@@ -0,0 +1,41 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	resetPackage(&Lifo{})
+	pkgFinal = &Lifo{}
+
+	defer func() { pkgVerifiers = nil }()
+
+	var order []string
+
+	queueEmpty := errors.New("queue is not empty")
+
+	Append(Fn(func() error {
+		order = append(order, "closer")
+		return nil
+	}))
+	AppendFinal(Fn(func() error {
+		order = append(order, "final")
+		return nil
+	}))
+	Verify(func(context.Context) error {
+		order = append(order, "verify")
+		return nil
+	})
+	Verify(func(context.Context) error {
+		return queueEmpty
+	})
+
+	err := Close()
+	assert.ErrorIs(t, err, ErrUnclean)
+	assert.ErrorIs(t, err, queueEmpty)
+	assert.Equal(t, []string{"closer", "verify", "final"}, order)
+}
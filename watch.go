@@ -0,0 +1,133 @@
+package shutdown
+
+import (
+	"context"
+	"time"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// FSWatcher is the part of *fsnotify.Watcher used by Watch,
+// so that this package does not depend on fsnotify.
+type FSWatcher interface {
+	WatchList() []string      // Returns the watched paths
+	Remove(name string) error // Stops watching the path
+	Close() error             // Releases the watcher and closes its channels
+}
+
+// WatchCloser stops a file watcher and the goroutine handling its events.
+type WatchCloser struct {
+	watcher FSWatcher     // The watcher to close
+	done    chan struct{} // Closed when the handling goroutine has exited
+}
+
+// Watch starts a goroutine handling the events and the errors of the watcher until its channels are closed,
+// and returns a closer stopping it, so that the inotify instance is not leaked across restarts:
+//
+//	w, _ := fsnotify.NewWatcher()
+//	shutdown.Append(shutdown.Watch(w, w.Events, w.Errors, onEvent, onError))
+//
+// The error handler is optional.
+func Watch[E any](w FSWatcher, events <-chan E, errs <-chan error, handle func(E), handleErr func(error)) *WatchCloser {
+	c := &WatchCloser{watcher: w, done: make(chan struct{})}
+
+	go func() {
+		defer close(c.done)
+
+		for events != nil || errs != nil {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					events = nil // Stop selecting the closed channel
+					continue
+				}
+
+				handle(event)
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil // Stop selecting the closed channel
+					continue
+				}
+
+				if handleErr != nil {
+					handleErr(err)
+				}
+			}
+		}
+	}()
+
+	return c
+}
+
+// CloseContext removes the watches, so that no new events are queued, closes the watcher
+// and waits until the pending events are handled and the goroutine has exited, or the context is done.
+func (c *WatchCloser) CloseContext(ctx context.Context) error {
+	var errs error
+
+	for _, name := range c.watcher.WatchList() {
+		errs = multierr.Append(errs, c.watcher.Remove(name))
+	}
+
+	errs = multierr.Append(errs, c.watcher.Close())
+
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out.
+		return multierr.Append(errs, ctx.Err())
+	case <-c.done: // The pending events are handled.
+		return errs
+	}
+}
+
+// Close stops the watcher without a deadline.
+func (c *WatchCloser) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+// LoopCloser stops a polling loop.
+type LoopCloser struct {
+	cancel context.CancelFunc // Cancels the context of the loop
+	done   chan struct{}      // Closed when the loop has exited
+}
+
+// PollLoop starts a goroutine calling poll every interval, e.g. to scan a directory where inotify
+// is unavailable, and returns a closer stopping it. The context passed to poll is cancelled on close,
+// so a running poll is expected to return early.
+func PollLoop(interval time.Duration, poll func(ctx context.Context)) *LoopCloser {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &LoopCloser{cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				poll(ctx)
+			}
+		}
+	}()
+
+	return c
+}
+
+// CloseContext stops the loop and waits until the running poll returns or the context is done.
+func (c *LoopCloser) CloseContext(ctx context.Context) error {
+	c.cancel()
+
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out.
+		return ctx.Err()
+	case <-c.done: // The loop has exited.
+		return nil
+	}
+}
+
+// Close stops the loop without a deadline.
+func (c *LoopCloser) Close() error {
+	return c.CloseContext(context.Background())
+}
@@ -0,0 +1,112 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWatcher mimics fsnotify.Watcher, queuing one last event on Close.
+type fakeWatcher struct {
+	mx      sync.Mutex
+	watched []string
+	events  chan string
+	errs    chan error
+}
+
+func (w *fakeWatcher) WatchList() []string {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	return append([]string(nil), w.watched...)
+}
+
+func (w *fakeWatcher) Remove(name string) error {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	for i, watched := range w.watched {
+		if watched == name {
+			w.watched = append(w.watched[:i], w.watched[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.New("not watched")
+}
+
+func (w *fakeWatcher) Close() error {
+	go func() {
+		w.events <- "pending"
+		close(w.events)
+		close(w.errs)
+	}()
+
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	w := &fakeWatcher{
+		watched: []string{"/etc/app", "/var/lib/app"},
+		events:  make(chan string),
+		errs:    make(chan error),
+	}
+
+	var (
+		mx      sync.Mutex
+		handled []string
+		failed  []error
+	)
+
+	c := Watch(w, w.events, w.errs, func(event string) {
+		mx.Lock()
+		handled = append(handled, event)
+		mx.Unlock()
+	}, func(err error) {
+		mx.Lock()
+		failed = append(failed, err)
+		mx.Unlock()
+	})
+
+	w.events <- "created"
+	w.errs <- errors.New("overflow")
+
+	require.NoError(t, c.Close())
+	assert.Empty(t, w.WatchList())
+	assert.Equal(t, []string{"created", "pending"}, handled)
+	assert.Len(t, failed, 1)
+}
+
+func TestPollLoop(t *testing.T) {
+	var polls atomic.Int32
+
+	c := PollLoop(time.Millisecond, func(ctx context.Context) {
+		polls.Add(1)
+	})
+
+	assert.Eventually(t, func() bool { return polls.Load() > 1 }, time.Second, time.Millisecond)
+	require.NoError(t, c.Close())
+
+	n := polls.Load()
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, n, polls.Load(), "Expected no poll after close")
+}
+
+func TestPollLoop_Timeout(t *testing.T) {
+	c := PollLoop(time.Millisecond, func(context.Context) {
+		time.Sleep(100 * time.Millisecond) // Ignores the cancellation
+	})
+
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, c.CloseContext(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,110 @@
+package shutdown
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// WeakRegistry is a closer of resources registered with Register, which are unregistered
+// automatically once the program drops their WeakHandle. It keeps a long-lived process from accumulating
+// the entries of resources that were closed elsewhere, e.g. per-request or per-session clients,
+// without the code having to unregister them explicitly.
+type WeakRegistry struct {
+	mx      sync.Mutex              // Mutex for thread safety of the entries
+	entries map[*weakEntry]struct{} // The registered resources
+}
+
+// weakEntry is a resource registered in a WeakRegistry. It does not refer to the handle of the resource,
+// so the registry does not keep the handle reachable.
+type weakEntry struct {
+	closer Closer // The registered resource
+}
+
+// WeakHandle is the handle of a resource registered in a WeakRegistry. The resource stays registered as long
+// as the handle is reachable; once the handle is garbage collected, a finalizer unregisters the resource.
+// The resource must not refer to its handle, otherwise the registry keeps the handle reachable.
+type WeakHandle[T Closer] struct {
+	resource T             // The registered resource
+	registry *WeakRegistry // The registry of the resource
+	entry    *weakEntry    // The entry of the resource in the registry
+	once     sync.Once     // Unregisters the resource once
+}
+
+// NewWeakRegistry creates a new empty WeakRegistry.
+func NewWeakRegistry() *WeakRegistry {
+	return &WeakRegistry{entries: make(map[*weakEntry]struct{})}
+}
+
+// Register registers the resource in the registry and returns its handle, through which the program
+// uses the resource. The resource is closed by the registry unless the handle is closed or dropped before:
+//
+//	h := shutdown.Register(registry, client)
+//	defer h.Close()
+//	h.Get().Do(req)
+func Register[T Closer](r *WeakRegistry, resource T) *WeakHandle[T] {
+	entry := &weakEntry{closer: resource}
+
+	r.mx.Lock()
+	r.entries[entry] = struct{}{}
+	r.mx.Unlock()
+
+	h := &WeakHandle[T]{resource: resource, registry: r, entry: entry}
+	runtime.SetFinalizer(h, func(h *WeakHandle[T]) { h.unregister() })
+
+	return h
+}
+
+// Get returns the registered resource.
+func (h *WeakHandle[T]) Get() T {
+	return h.resource
+}
+
+// Close closes the resource and unregisters it.
+func (h *WeakHandle[T]) Close() error {
+	h.unregister()
+	runtime.SetFinalizer(h, nil) // Nothing is left to unregister
+
+	return h.resource.Close()
+}
+
+// unregister removes the resource from its registry.
+func (h *WeakHandle[T]) unregister() {
+	h.once.Do(func() {
+		h.registry.mx.Lock()
+		defer h.registry.mx.Unlock()
+
+		delete(h.registry.entries, h.entry)
+	})
+}
+
+// Len returns the number of the registered resources.
+func (r *WeakRegistry) Len() int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return len(r.entries)
+}
+
+// CloseContext closes the registered resources with the context and unregisters them.
+func (r *WeakRegistry) CloseContext(ctx context.Context) error {
+	r.mx.Lock()
+	entries := r.entries
+	r.entries = make(map[*weakEntry]struct{})
+	r.mx.Unlock()
+
+	var errs error
+
+	for entry := range entries {
+		errs = multierr.Append(errs, closeCtx(ctx, entry.closer))
+	}
+
+	return errs
+}
+
+// Close closes the registered resources and unregisters them.
+func (r *WeakRegistry) Close() error {
+	return r.CloseContext(context.Background())
+}
@@ -0,0 +1,42 @@
+package shutdown
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeakRegistry(t *testing.T) {
+	registry := NewWeakRegistry()
+
+	kept := &mockCloser{}
+	h := Register(registry, kept)
+	assert.Equal(t, kept, h.Get())
+
+	closedElsewhere := Register(registry, &mockCloser{})
+	require.NoError(t, closedElsewhere.Close())
+	assert.Equal(t, 1, registry.Len())
+
+	func() {
+		_ = Register(registry, &mockCloser{}) // Dropped right away
+	}()
+
+	assert.Eventually(t, func() bool {
+		runtime.GC() // The finalizer of the dropped handle unregisters its resource
+		return registry.Len() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	closed := false
+	kept.closeFunc = func() error {
+		closed = true
+		return nil
+	}
+
+	require.NoError(t, registry.Close())
+	assert.True(t, closed)
+	assert.Zero(t, registry.Len())
+	runtime.KeepAlive(h)
+}
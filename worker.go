@@ -0,0 +1,52 @@
+package shutdown
+
+import (
+	"context"
+
+	"github.com/partyzanex/shutdown/internal/multierr"
+)
+
+// WorkerCloser stops a background-job worker and then closes its clients.
+type WorkerCloser struct {
+	stop    func()   // Stops polling and waits for the running jobs
+	clients []Closer // Clients closed after the worker has stopped
+}
+
+// Worker returns a closer for a background-job worker, such as a Temporal worker (worker.Stop),
+// an Asynq server (Server.Shutdown) or a machinery worker (Worker.Quit).
+// The stop function is expected to stop polling for new jobs and to block until the running jobs finish;
+// the clients are closed once it returns, in the given order.
+func Worker(stop func(), clients ...Closer) *WorkerCloser {
+	return &WorkerCloser{stop: stop, clients: clients}
+}
+
+// CloseContext stops the worker, waiting for the running jobs until the context is done,
+// and then closes the clients. If the context is done first, the clients are closed anyway,
+// since the process is about to exit.
+func (w *WorkerCloser) CloseContext(ctx context.Context) error {
+	stopped := make(chan struct{}) // Channel to signal that the worker has stopped
+
+	go func() {
+		w.stop()
+		close(stopped)
+	}()
+
+	var errs error
+
+	select {
+	case <-ctx.Done(): // If the context is cancelled or times out.
+		errs = ctx.Err()
+	case <-stopped: // The worker has stopped, the running jobs are finished.
+	}
+
+	for _, client := range w.clients {
+		errs = multierr.Append(errs, client.Close())
+	}
+
+	return errs
+}
+
+// Close stops the worker and closes the clients without a deadline.
+func (w *WorkerCloser) Close() error {
+	return w.CloseContext(context.Background())
+}
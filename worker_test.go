@@ -0,0 +1,40 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorker(t *testing.T) {
+	var order []string
+
+	stop := func() {
+		time.Sleep(10 * time.Millisecond)
+		order = append(order, "worker")
+	}
+	client := Fn(func() error {
+		order = append(order, "client")
+		return nil
+	})
+
+	assert.NoError(t, Worker(stop, client).Close())
+	assert.Equal(t, []string{"worker", "client"}, order)
+}
+
+func TestWorker_Deadline(t *testing.T) {
+	clientClosed := false
+	client := Fn(func() error {
+		clientClosed = true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := Worker(func() { time.Sleep(time.Second) }, client).CloseContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, clientClosed)
+}